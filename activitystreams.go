@@ -0,0 +1,52 @@
+package gofeed
+
+import (
+	"bytes"
+	"context"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/mmcdole/gofeed/activitystreams"
+)
+
+// FeedTypeActivityStreams represents an ActivityStreams 2.0
+// collection, such as the outbox of an ActivityPub actor.
+var FeedTypeActivityStreams = RegisterFormat(Format{
+	Name:   "activitystreams",
+	Detect: detectActivityStreams,
+	Parse: func(data []byte, ctx context.Context) (interface{}, error) {
+		return (&activitystreams.Parser{}).ParseBytes(data)
+	},
+	Translator: func(fp *Parser) Translator {
+		return fp.activityStreamsTrans()
+	},
+})
+
+// detectActivityStreams reports whether a document is an
+// ActivityStreams collection.  Only the members needed to tell
+// are decoded, the document is decoded in full when parsed.
+func detectActivityStreams(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \r\n\t\xef\xbb\xbf")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	doc := map[string]interface{}{}
+	iter := jsoniter.ConfigDefault.BorrowIterator(trimmed)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, name string) bool {
+		if name == "type" || name == "@context" {
+			doc[name] = iter.Read()
+		} else {
+			iter.Skip()
+			doc[name] = nil
+		}
+		return iter.Error == nil
+	})
+	return iter.Error == nil && activitystreams.IsCollection(doc)
+}
+
+func (f *Parser) activityStreamsTrans() Translator {
+	if f.ActivityStreamsTranslator != nil {
+		return f.ActivityStreamsTranslator
+	}
+	return &DefaultActivityStreamsTranslator{TranslatorOptions: f.translatorOptions()}
+}
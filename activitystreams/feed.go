@@ -0,0 +1,95 @@
+package activitystreams
+
+import (
+	"encoding/json"
+)
+
+// Collection is an ActivityStreams 2.0 OrderedCollection or
+// OrderedCollectionPage, such as the outbox of an ActivityPub
+// actor, or their unordered counterparts.
+// https://www.w3.org/TR/activitystreams-core/#collections
+type Collection struct {
+	ID         string `json:"id,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Summary    string `json:"summary,omitempty"`
+	TotalItems int    `json:"totalItems,omitempty"`
+	// First is the id of the first page of a collection.  The
+	// items and next page of a first page which is embedded in
+	// the collection are taken as those of the collection.
+	First  string `json:"first,omitempty"`
+	Next   string `json:"next,omitempty"`
+	Prev   string `json:"prev,omitempty"`
+	PartOf string `json:"partOf,omitempty"` // the collection a page belongs to
+	// Items holds the orderedItems or items of the collection.
+	// Objects which aren't wrapped in an activity are given an
+	// Activity of their own, with only the Object set.
+	Items []*Activity `json:"items,omitempty"`
+}
+
+func (c Collection) String() string {
+	json, _ := json.MarshalIndent(c, "", "    ")
+	return string(json)
+}
+
+// Activity is an activity of a collection, such as the
+// Create activity of a new post.
+type Activity struct {
+	ID        string   `json:"id,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Actor     *Actor   `json:"actor,omitempty"`
+	Published string   `json:"published,omitempty"`
+	To        []string `json:"to,omitempty"`
+	CC        []string `json:"cc,omitempty"`
+	// Object is the object of the activity, such as a Note or
+	// Article.  Objects only given by their id have only the ID.
+	Object *Object `json:"object,omitempty"`
+}
+
+// Object is an object of an activity, such as a Note or Article.
+type Object struct {
+	ID           string        `json:"id,omitempty"`
+	Type         string        `json:"type,omitempty"`
+	Name         string        `json:"name,omitempty"`
+	Summary      string        `json:"summary,omitempty"`
+	Content      string        `json:"content,omitempty"`
+	MediaType    string        `json:"mediaType,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	Published    string        `json:"published,omitempty"`
+	Updated      string        `json:"updated,omitempty"`
+	AttributedTo *Actor        `json:"attributedTo,omitempty"`
+	InReplyTo    string        `json:"inReplyTo,omitempty"`
+	Sensitive    bool          `json:"sensitive,omitempty"`
+	Attachments  []*Attachment `json:"attachments,omitempty"`
+	Tags         []*Tag        `json:"tags,omitempty"`
+}
+
+// Actor is the actor of an activity or the author of an object.
+// Actors only given by their id have only the ID.
+type Actor struct {
+	ID                string `json:"id,omitempty"`
+	Type              string `json:"type,omitempty"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferredUsername,omitempty"`
+	URL               string `json:"url,omitempty"`
+	Icon              string `json:"icon,omitempty"`
+}
+
+// Attachment is a document attached to an object,
+// such as an image or a video.
+type Attachment struct {
+	Type      string `json:"type,omitempty"`
+	MediaType string `json:"mediaType,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Name      string `json:"name,omitempty"` // the description, or alt text
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+}
+
+// Tag is a tag of an object, such as
+// a Hashtag or a Mention.
+type Tag struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+	Href string `json:"href,omitempty"`
+}
@@ -0,0 +1,312 @@
+package activitystreams
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/mmcdole/gofeed/internal/shared"
+)
+
+var (
+	j = jsoniter.ConfigCompatibleWithStandardLibrary
+)
+
+// ErrNotCollection is returned for documents which
+// aren't an ActivityStreams collection.
+var ErrNotCollection = errors.New("Document is not an ActivityStreams collection")
+
+// Namespace is the JSON-LD context of ActivityStreams 2.0.
+const Namespace = "https://www.w3.org/ns/activitystreams"
+
+// Parser is an ActivityStreams 2.0 collection parser.
+type Parser struct{}
+
+// Parse parses an ActivityStreams collection, such as
+// an outbox or a page of one, into a Collection.
+func (ap *Parser) Parse(feed io.Reader) (*Collection, error) {
+	return ap.ParseWithContext(feed, context.Background())
+}
+
+// ParseWithContext is like Parse but stops with the error of
+// the context once it's done.  The context is checked as the
+// document is read.
+func (ap *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Collection, error) {
+	buffer := new(bytes.Buffer)
+	if _, err := buffer.ReadFrom(shared.NewContextReader(ctx, feed)); err != nil {
+		return nil, err
+	}
+	return ap.ParseBytes(buffer.Bytes())
+}
+
+// ParseBytes parses a collection held in memory into a Collection.
+func (ap *Parser) ParseBytes(feed []byte) (*Collection, error) {
+	var doc map[string]interface{}
+	if err := j.Unmarshal(feed, &doc); err != nil {
+		return nil, err
+	}
+	if !IsCollection(doc) {
+		return nil, ErrNotCollection
+	}
+	return parseCollection(doc), nil
+}
+
+// IsCollection reports whether a decoded JSON object is an
+// ActivityStreams collection: one of the collection types, or
+// an object in the ActivityStreams context with items.
+func IsCollection(doc map[string]interface{}) bool {
+	switch str(doc["type"]) {
+	case "OrderedCollection", "OrderedCollectionPage", "Collection", "CollectionPage":
+		return true
+	}
+	if !hasContext(doc) {
+		return false
+	}
+	for _, key := range []string{"orderedItems", "items", "first"} {
+		if _, ok := doc[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasContext reports whether the @context of
+// an object includes ActivityStreams.
+func hasContext(doc map[string]interface{}) bool {
+	for _, c := range list(doc["@context"]) {
+		if strings.TrimSuffix(str(c), "#") == Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCollection(doc map[string]interface{}) *Collection {
+	c := &Collection{}
+	c.ID = str(doc["id"])
+	c.Type = str(doc["type"])
+	c.Name = str(doc["name"])
+	c.Summary = str(doc["summary"])
+	c.TotalItems = num(doc["totalItems"])
+	c.First = ref(doc["first"])
+	c.Next = ref(doc["next"])
+	c.Prev = ref(doc["prev"])
+	c.PartOf = ref(doc["partOf"])
+	c.Items = parseItems(doc)
+
+	if first, ok := doc["first"].(map[string]interface{}); ok && len(c.Items) == 0 {
+		c.Items = parseItems(first)
+		c.Next = ref(first["next"])
+	}
+	return c
+}
+
+func parseItems(doc map[string]interface{}) (items []*Activity) {
+	values, ok := doc["orderedItems"]
+	if !ok {
+		values = doc["items"]
+	}
+	for _, v := range list(values) {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		items = append(items, parseActivity(item))
+	}
+	return
+}
+
+func parseActivity(doc map[string]interface{}) *Activity {
+	if _, ok := doc["object"]; !ok {
+		// An object on its own rather than an activity
+		return &Activity{Object: parseObject(doc)}
+	}
+
+	a := &Activity{}
+	a.ID = str(doc["id"])
+	a.Type = str(doc["type"])
+	a.Actor = parseActor(doc["actor"])
+	a.Published = str(doc["published"])
+	a.To = refs(doc["to"])
+	a.CC = refs(doc["cc"])
+	switch object := first(doc["object"]).(type) {
+	case map[string]interface{}:
+		a.Object = parseObject(object)
+	case string:
+		a.Object = &Object{ID: object}
+	}
+	return a
+}
+
+func parseObject(doc map[string]interface{}) *Object {
+	o := &Object{}
+	o.ID = str(doc["id"])
+	o.Type = str(doc["type"])
+	o.Name = mapped(doc, "name")
+	o.Summary = mapped(doc, "summary")
+	o.Content = mapped(doc, "content")
+	o.MediaType = str(doc["mediaType"])
+	o.URL = link(doc["url"])
+	o.Published = str(doc["published"])
+	o.Updated = str(doc["updated"])
+	o.AttributedTo = parseActor(doc["attributedTo"])
+	o.InReplyTo = ref(doc["inReplyTo"])
+	o.Sensitive, _ = doc["sensitive"].(bool)
+	for _, v := range list(doc["attachment"]) {
+		if attachment, ok := v.(map[string]interface{}); ok {
+			o.Attachments = append(o.Attachments, parseAttachment(attachment))
+		}
+	}
+	for _, v := range list(doc["tag"]) {
+		if tag, ok := v.(map[string]interface{}); ok {
+			o.Tags = append(o.Tags, &Tag{
+				Type: str(tag["type"]),
+				Name: str(tag["name"]),
+				Href: link(tag["href"]),
+			})
+		}
+	}
+	return o
+}
+
+// parseActor parses the first actor of an actor or
+// attributedTo property, which may only be an id.
+func parseActor(v interface{}) *Actor {
+	switch actor := first(v).(type) {
+	case string:
+		return &Actor{ID: actor}
+	case map[string]interface{}:
+		return &Actor{
+			ID:                str(actor["id"]),
+			Type:              str(actor["type"]),
+			Name:              mapped(actor, "name"),
+			PreferredUsername: str(actor["preferredUsername"]),
+			URL:               link(actor["url"]),
+			Icon:              link(actor["icon"]),
+		}
+	}
+	return nil
+}
+
+func parseAttachment(doc map[string]interface{}) *Attachment {
+	a := &Attachment{}
+	a.Type = str(doc["type"])
+	a.MediaType = str(doc["mediaType"])
+	a.URL = link(doc["url"])
+	a.Name = mapped(doc, "name")
+	a.Width = num(doc["width"])
+	a.Height = num(doc["height"])
+	// Links to the document carry its media type
+	if url, ok := first(doc["url"]).(map[string]interface{}); ok && a.MediaType == "" {
+		a.MediaType = str(url["mediaType"])
+	}
+	return a
+}
+
+// mapped returns a natural language property, or failing
+// that the first value of its map of languages, such as
+// contentMap for content.
+func mapped(doc map[string]interface{}, name string) string {
+	if value := str(doc[name]); value != "" {
+		return value
+	}
+	languages, _ := doc[name+"Map"].(map[string]interface{})
+	if value := str(languages["und"]); value != "" {
+		return value
+	}
+	var value string
+	var language string
+	for l, v := range languages {
+		// Take the first language in order, as
+		// maps have no order of their own.
+		if s := str(v); s != "" && (language == "" || l < language) {
+			language, value = l, s
+		}
+	}
+	return value
+}
+
+// link returns the URL of a property which is a URL, a Link or
+// an object with a url, or a list of them.  The first text/html
+// link is preferred over links to other media types.
+func link(v interface{}) string {
+	var links []string
+	for _, l := range list(v) {
+		switch l := l.(type) {
+		case string:
+			links = append(links, l)
+		case map[string]interface{}:
+			href := str(l["href"])
+			if href == "" {
+				href = link(l["url"])
+			}
+			if href == "" {
+				continue
+			}
+			if str(l["mediaType"]) == "text/html" {
+				return href
+			}
+			links = append(links, href)
+		}
+	}
+	if len(links) > 0 {
+		return links[0]
+	}
+	return ""
+}
+
+// ref returns the id of the first object
+// of a property, or its href for links.
+func ref(v interface{}) string {
+	switch v := first(v).(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if id := str(v["id"]); id != "" {
+			return id
+		}
+		return str(v["href"])
+	}
+	return ""
+}
+
+func refs(v interface{}) (ids []string) {
+	for _, elem := range list(v) {
+		if id := ref(elem); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+// list returns the values of a property, which
+// are either an array or a single value.
+func list(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	}
+	return []interface{}{v}
+}
+
+func first(v interface{}) interface{} {
+	if values := list(v); len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
+
+func num(v interface{}) int {
+	f, _ := v.(float64)
+	return int(f)
+}
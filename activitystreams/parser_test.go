@@ -0,0 +1,82 @@
+package activitystreams_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed/activitystreams"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests
+
+func TestParser_Parse(t *testing.T) {
+	files, _ := filepath.Glob("../testdata/parser/activitystreams/*.json")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		if strings.HasSuffix(name, "expected") {
+			continue
+		}
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("../testdata/parser/activitystreams/%s.json", name)
+		f, _ := os.ReadFile(ff)
+
+		// Parse actual feed
+		fp := &activitystreams.Parser{}
+		actual, _ := fp.Parse(bytes.NewReader(f))
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("../testdata/parser/activitystreams/%s_expected.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &activitystreams.Collection{}
+		json.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.json did not match expected output %s_expected.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestParser_ParseNotCollection(t *testing.T) {
+	f, _ := os.ReadFile("../testdata/parser/activitystreams/invalid/not_collection.json")
+
+	fp := &activitystreams.Parser{}
+	collection, err := fp.Parse(bytes.NewReader(f))
+	assert.Nil(t, collection)
+	assert.Equal(t, activitystreams.ErrNotCollection, err)
+
+	collection, err = fp.Parse(strings.NewReader(`{"type": "OrderedCollection"`))
+	assert.Nil(t, collection)
+	assert.NotNil(t, err)
+}
+
+func TestParser_ParseWithContext(t *testing.T) {
+	feedData := `{"type": "OrderedCollection", "orderedItems": [{"type": "Note", "content": "Hi"}]}`
+
+	fp := &activitystreams.Parser{}
+	collection, err := fp.ParseWithContext(strings.NewReader(feedData), context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Hi", collection.Items[0].Object.Content)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	collection, err = fp.ParseWithContext(strings.NewReader(feedData), ctx)
+	assert.Nil(t, collection)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
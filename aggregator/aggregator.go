@@ -0,0 +1,187 @@
+package aggregator
+
+import (
+	"context"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/health"
+	"github.com/mmcdole/gofeed/scheduler"
+)
+
+// Aggregator polls the subscriptions of a Store and reports
+// the items which haven't been seen before.  The fetch state of
+// each feed is saved to the store after every poll, so polling
+// picks up where it left off when the aggregator is restarted.
+// Aggregators must be created with NewAggregator.
+type Aggregator struct {
+	Store     Store
+	Scheduler *scheduler.Scheduler
+	// Handler is called with the items of a feed which
+	// no earlier poll has seen.  It's called from the
+	// scheduler's worker goroutines.
+	Handler func(sub *Subscription, items []*gofeed.Item)
+	// ErrorHandler, if set, is called with errors, both of
+	// polls and of the store.  sub is nil for store errors
+	// which don't concern a single subscription.
+	ErrorHandler func(sub *Subscription, err error)
+	// SeenRetention is how long items stay marked as seen
+	// after they drop out of their feed, so that items which
+	// come back aren't reported again.  Zero forgets them as
+	// soon as they drop out.
+	SeenRetention time.Duration
+}
+
+// DefaultSeenRetention is the SeenRetention of
+// aggregators created by NewAggregator.
+const DefaultSeenRetention = 30 * 24 * time.Hour
+
+// NewAggregator creates an aggregator for the subscriptions
+// of a store, which are fetched with the parser.  A nil parser
+// uses gofeed.NewParser.
+func NewAggregator(store Store, parser *gofeed.Parser) *Aggregator {
+	a := &Aggregator{
+		Store:         store,
+		Scheduler:     scheduler.New(parser),
+		SeenRetention: DefaultSeenRetention,
+	}
+	a.Scheduler.Handler = a.handle
+	return a
+}
+
+// Subscribe adds a subscription to a feed, which is polled
+// as soon as possible.  Subscribing to a feed twice keeps
+// the existing subscription.
+func (a *Aggregator) Subscribe(feedURL string) error {
+	added, err := a.Store.Add(&Subscription{URL: feedURL, Added: time.Now()})
+	if err != nil || !added {
+		return err
+	}
+	a.Scheduler.Add(feedURL)
+	return nil
+}
+
+// Unsubscribe stops polling a feed and deletes its subscription.
+func (a *Aggregator) Unsubscribe(feedURL string) error {
+	a.Scheduler.Remove(feedURL)
+	return a.Store.Delete(feedURL)
+}
+
+// Run schedules the subscriptions in the store and polls them as
+// they fall due until the context is done, and returns its error.
+// The store is flushed before Run returns.
+func (a *Aggregator) Run(ctx context.Context) error {
+	subs, err := a.Store.Subscriptions()
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		a.Scheduler.AddWithState(sub.URL, sub.State)
+	}
+	err = a.Scheduler.Run(ctx)
+	if ferr := a.Store.Flush(); ferr != nil {
+		a.error(nil, ferr)
+	}
+	return err
+}
+
+// Poll polls a subscription now, and returns
+// the error of the poll if it failed.
+func (a *Aggregator) Poll(ctx context.Context, feedURL string) error {
+	sub, err := a.Store.Subscription(feedURL)
+	if err != nil {
+		return err
+	}
+	if _, ok := a.Scheduler.State(feedURL); !ok {
+		a.Scheduler.AddWithState(feedURL, sub.State)
+	}
+	return a.Scheduler.Poll(ctx, feedURL).Err
+}
+
+// handle saves the outcome of a poll to the store and passes
+// the unseen items to the Handler.  Items are only marked as
+// seen once the Handler has returned, so that none are lost
+// if the aggregator stops while handling them.  Seen items
+// which have been out of the feed for longer than the
+// SeenRetention are then forgotten.
+func (a *Aggregator) handle(result *scheduler.Result) {
+	sub, err := a.Store.Subscription(result.URL)
+	if err == ErrNotFound {
+		// Unsubscribed while being polled
+		return
+	}
+	if err != nil {
+		a.error(nil, err)
+		return
+	}
+
+	if state, ok := a.Scheduler.State(result.URL); ok {
+		sub.State = state
+	}
+	sub.LastError = ""
+	if result.Err != nil {
+		sub.LastError = result.Err.Error()
+		a.error(sub, result.Err)
+	}
+	if result.Feed != nil && result.Feed.Title != "" {
+		sub.Title = result.Feed.Title
+	}
+	sub.Health.Record(health.Result{
+		URL:               result.URL,
+		Feed:              result.Feed,
+		Err:               result.Err,
+		StatusCode:        result.StatusCode,
+		FinalURL:          result.FinalURL,
+		PermanentRedirect: result.PermanentRedirect,
+		Time:              result.Time,
+	})
+
+	var items []*gofeed.Item
+	var ids []string
+	for _, item := range result.NewItems {
+		id := item.Identity()
+		seen, err := a.Store.Seen(sub.URL, id)
+		if err != nil {
+			a.error(sub, err)
+			return
+		}
+		if !seen {
+			items = append(items, item)
+			ids = append(ids, id)
+		}
+	}
+
+	if err := a.Store.Save(sub); err != nil {
+		a.error(sub, err)
+		return
+	}
+	if len(items) > 0 {
+		if a.Handler != nil {
+			a.Handler(sub, items)
+		}
+		if err := a.Store.MarkSeen(sub.URL, ids...); err != nil {
+			a.error(sub, err)
+			return
+		}
+	}
+
+	if result.Feed == nil {
+		return
+	}
+	var present []string
+	for _, item := range result.Feed.Items {
+		if item != nil {
+			present = append(present, item.Identity())
+		}
+	}
+	before := time.Now().Add(-a.SeenRetention)
+	if err := a.Store.PruneSeen(sub.URL, present, before); err != nil {
+		a.error(sub, err)
+	}
+}
+
+func (a *Aggregator) error(sub *Subscription, err error) {
+	if a.ErrorHandler != nil {
+		a.ErrorHandler(sub, err)
+	}
+}
@@ -0,0 +1,177 @@
+package aggregator_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/aggregator"
+	"github.com/stretchr/testify/assert"
+)
+
+// feedServer serves an RSS feed with the given items,
+// which can be changed between requests.
+type feedServer struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (f *feedServer) setItems(items ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items = items
+}
+
+func (f *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/feed" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	io.WriteString(w, `<rss version="2.0"><channel><title>Test Feed</title>`)
+	for _, guid := range f.items {
+		fmt.Fprintf(w, "<item><guid>%s</guid><title>Item %s</title></item>", guid, guid)
+	}
+	io.WriteString(w, `</channel></rss>`)
+}
+
+func TestAggregator_Poll(t *testing.T) {
+	feed := &feedServer{}
+	feed.setItems("1", "2")
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	store, err := aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+
+	var received []string
+	handler := func(sub *aggregator.Subscription, items []*gofeed.Item) {
+		for _, item := range items {
+			received = append(received, item.GUID)
+		}
+	}
+
+	a := aggregator.NewAggregator(store, nil)
+	a.Handler = handler
+	feedURL := server.URL + "/feed"
+	assert.Nil(t, a.Subscribe(feedURL))
+	assert.Nil(t, a.Subscribe(feedURL))
+
+	assert.Nil(t, a.Poll(context.Background(), feedURL))
+	assert.Equal(t, []string{"1", "2"}, received)
+
+	sub, err := store.Subscription(feedURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "Test Feed", sub.Title)
+	assert.False(t, sub.State.LastSuccess.IsZero())
+
+	// A restarted aggregator only reports unseen items, so
+	// items which come back after dropping out are skipped
+	feed.setItems("3", "1")
+	assert.Nil(t, a.Poll(context.Background(), feedURL))
+	feed.setItems("3", "2")
+
+	assert.Nil(t, store.Flush())
+	store, err = aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+	a = aggregator.NewAggregator(store, nil)
+	a.Handler = handler
+	assert.Nil(t, a.Poll(context.Background(), feedURL))
+	assert.Equal(t, []string{"1", "2", "3"}, received)
+
+	_, err = store.Subscription(feedURL)
+	assert.Nil(t, err)
+	assert.Nil(t, a.Unsubscribe(feedURL))
+	assert.Equal(t, aggregator.ErrNotFound, a.Poll(context.Background(), feedURL))
+}
+
+func TestAggregator_SeenRetention(t *testing.T) {
+	feed := &feedServer{}
+	feed.setItems("1", "2")
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	var received []string
+	a := aggregator.NewAggregator(aggregator.NewMemoryStore(), nil)
+	a.Handler = func(sub *aggregator.Subscription, items []*gofeed.Item) {
+		for _, item := range items {
+			received = append(received, item.GUID)
+		}
+	}
+	a.SeenRetention = 0
+
+	feedURL := server.URL + "/feed"
+	assert.Nil(t, a.Subscribe(feedURL))
+	assert.Nil(t, a.Poll(context.Background(), feedURL))
+
+	// Items are forgotten as soon as they drop out
+	feed.setItems("1")
+	assert.Nil(t, a.Poll(context.Background(), feedURL))
+	seen, _ := a.Store.Seen(feedURL, "guid:2")
+	assert.False(t, seen)
+	seen, _ = a.Store.Seen(feedURL, "guid:1")
+	assert.True(t, seen)
+
+	feed.setItems("2", "1")
+	assert.Nil(t, a.Poll(context.Background(), feedURL))
+	assert.Equal(t, []string{"1", "2", "2"}, received)
+}
+
+func TestAggregator_PollFailure(t *testing.T) {
+	server := httptest.NewServer(&feedServer{})
+	defer server.Close()
+
+	var errs []error
+	a := aggregator.NewAggregator(aggregator.NewMemoryStore(), nil)
+	a.ErrorHandler = func(sub *aggregator.Subscription, err error) {
+		errs = append(errs, err)
+	}
+
+	feedURL := server.URL + "/missing"
+	assert.Nil(t, a.Subscribe(feedURL))
+	err := a.Poll(context.Background(), feedURL)
+	assert.Equal(t, gofeed.HTTPError{StatusCode: 404, Status: "404 Not Found"}, err)
+	assert.Equal(t, []error{err}, errs)
+
+	sub, _ := a.Store.Subscription(feedURL)
+	assert.Equal(t, 1, sub.State.Failures)
+	assert.Equal(t, "http error: 404 Not Found", sub.LastError)
+	assert.Equal(t, 1, sub.Health.ConsecutiveFailures)
+	assert.Equal(t, 404, sub.Health.LastStatus)
+}
+
+func TestAggregator_Run(t *testing.T) {
+	feed := &feedServer{}
+	feed.setItems("1")
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	store := aggregator.NewMemoryStore()
+	store.Save(&aggregator.Subscription{URL: server.URL + "/feed"})
+
+	done := make(chan []*gofeed.Item, 1)
+	a := aggregator.NewAggregator(store, nil)
+	a.Handler = func(sub *aggregator.Subscription, items []*gofeed.Item) {
+		done <- items
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Run(ctx)
+
+	select {
+	case items := <-done:
+		assert.Len(t, items, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription was not polled")
+	}
+}
@@ -0,0 +1,174 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultWriteDelay is the WriteDelay of the
+// stores opened by OpenFileStore.
+const DefaultWriteDelay = time.Second
+
+// FileStore is a Store which keeps everything in memory and
+// saves it to a JSON file.  Changes made within WriteDelay of
+// each other are saved together, and Flush saves them at once.
+// The file is replaced atomically, so it's never left half
+// written.
+type FileStore struct {
+	MemoryStore
+	// WriteDelay is how long the store waits after a change
+	// before saving it.  Zero saves every change at once.
+	WriteDelay time.Duration
+
+	path  string
+	timer *time.Timer
+	// err is the error of the last delayed write,
+	// returned by the next change or Flush.
+	err error
+}
+
+// OpenFileStore opens the store saved in the file at path.
+// The file is created by the first change if it doesn't exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{WriteDelay: DefaultWriteDelay, path: path}
+	s.data = newStoreData()
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.data); err != nil {
+		return nil, err
+	}
+	if s.data.Subscriptions == nil {
+		s.data.Subscriptions = map[string]*Subscription{}
+	}
+	if s.data.Seen == nil {
+		s.data.Seen = map[string]map[string]time.Time{}
+	}
+	return s, nil
+}
+
+// Add saves a subscription unless the feed is already
+// subscribed to, and reports whether it was saved.
+func (s *FileStore) Add(sub *Subscription) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.data.add(sub) {
+		return false, nil
+	}
+	return true, s.changed()
+}
+
+// Save adds or replaces a subscription.
+func (s *FileStore) Save(sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.save(sub)
+	return s.changed()
+}
+
+// Delete removes a subscription and its seen items.
+func (s *FileStore) Delete(feedURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.delete(feedURL)
+	return s.changed()
+}
+
+// MarkSeen records that items of a feed were seen.
+func (s *FileStore) MarkSeen(feedURL string, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.markSeen(feedURL, ids)
+	return s.changed()
+}
+
+// PruneSeen notes that the seen items of a feed listed in
+// ids are still in it, and forgets the others which haven't
+// been in it since before the given time.
+func (s *FileStore) PruneSeen(feedURL string, ids []string, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.pruneSeen(feedURL, ids, before)
+	return s.changed()
+}
+
+// Flush saves the changes which are waiting to be saved,
+// and returns the error of any earlier delayed write.
+func (s *FileStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+		s.err = s.write()
+	}
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// changed saves the store, or schedules a write if one
+// isn't already waiting, and returns the error of the last
+// delayed write.  It must be called with the lock held.
+func (s *FileStore) changed() error {
+	err := s.err
+	s.err = nil
+	if s.WriteDelay <= 0 {
+		if werr := s.write(); werr != nil {
+			return werr
+		}
+		return err
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.WriteDelay, s.delayedWrite)
+	}
+	return err
+}
+
+func (s *FileStore) delayedWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer == nil {
+		// Flushed in the meantime
+		return
+	}
+	s.timer = nil
+	s.err = s.write()
+}
+
+// write saves the store to a temporary file which
+// then replaces the store's file.  It must be called
+// with the lock held.
+func (s *FileStore) write() error {
+	b, err := json.Marshal(&s.data)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
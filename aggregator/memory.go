@@ -0,0 +1,177 @@
+package aggregator
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store which keeps everything in memory.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data storeData
+}
+
+// storeData is the content of a store, which
+// FileStore saves as JSON.
+type storeData struct {
+	Subscriptions map[string]*Subscription `json:"subscriptions"`
+	// Seen holds the time each seen item
+	// was last known to be in its feed.
+	Seen map[string]map[string]time.Time `json:"seen"`
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: newStoreData()}
+}
+
+func newStoreData() storeData {
+	return storeData{
+		Subscriptions: map[string]*Subscription{},
+		Seen:          map[string]map[string]time.Time{},
+	}
+}
+
+// Subscriptions returns all subscriptions, ordered by URL.
+func (s *MemoryStore) Subscriptions() ([]*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subs := make([]*Subscription, 0, len(s.data.Subscriptions))
+	for _, sub := range s.data.Subscriptions {
+		subs = append(subs, copySubscription(sub))
+	}
+	sort.Slice(subs, func(i, k int) bool { return subs[i].URL < subs[k].URL })
+	return subs, nil
+}
+
+// Subscription returns the subscription to a feed, or ErrNotFound.
+func (s *MemoryStore) Subscription(feedURL string) (*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sub, ok := s.data.Subscriptions[feedURL]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copySubscription(sub), nil
+}
+
+// Add saves a subscription unless the feed is already
+// subscribed to, and reports whether it was saved.
+func (s *MemoryStore) Add(sub *Subscription) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.add(sub), nil
+}
+
+// Save adds or replaces a subscription.
+func (s *MemoryStore) Save(sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.save(sub)
+	return nil
+}
+
+// Delete removes a subscription and its seen items.
+func (s *MemoryStore) Delete(feedURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.delete(feedURL)
+	return nil
+}
+
+// MarkSeen records that items of a feed were seen.
+func (s *MemoryStore) MarkSeen(feedURL string, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.markSeen(feedURL, ids)
+	return nil
+}
+
+// Seen reports whether an item of a feed was seen.
+func (s *MemoryStore) Seen(feedURL, id string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.data.Seen[feedURL][id]
+	return ok, nil
+}
+
+// PruneSeen notes that the seen items of a feed listed in
+// ids are still in it, and forgets the others which haven't
+// been in it since before the given time.
+func (s *MemoryStore) PruneSeen(feedURL string, ids []string, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.pruneSeen(feedURL, ids, before)
+	return nil
+}
+
+// Flush does nothing, as a MemoryStore
+// holds nothing back.
+func (s *MemoryStore) Flush() error {
+	return nil
+}
+
+func (d *storeData) add(sub *Subscription) bool {
+	if _, ok := d.Subscriptions[sub.URL]; ok {
+		return false
+	}
+	d.save(sub)
+	return true
+}
+
+func (d *storeData) save(sub *Subscription) {
+	d.Subscriptions[sub.URL] = copySubscription(sub)
+}
+
+func (d *storeData) delete(feedURL string) {
+	delete(d.Subscriptions, feedURL)
+	delete(d.Seen, feedURL)
+}
+
+func (d *storeData) markSeen(feedURL string, ids []string) {
+	seen := d.Seen[feedURL]
+	if seen == nil {
+		seen = map[string]time.Time{}
+		d.Seen[feedURL] = seen
+	}
+	now := time.Now()
+	for _, id := range ids {
+		seen[id] = now
+	}
+}
+
+func (d *storeData) pruneSeen(feedURL string, ids []string, before time.Time) {
+	seen := d.Seen[feedURL]
+	now := time.Now()
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			seen[id] = now
+		}
+	}
+	for id, last := range seen {
+		if last.Before(before) {
+			delete(seen, id)
+		}
+	}
+	if len(seen) == 0 {
+		delete(d.Seen, feedURL)
+	}
+}
+
+// copySubscription returns a copy of a subscription
+// which shares nothing with it.
+func copySubscription(sub *Subscription) *Subscription {
+	c := *sub
+	if sub.State.Items != nil {
+		c.State.Items = make(map[string]bool, len(sub.State.Items))
+		for id, v := range sub.State.Items {
+			c.State.Items[id] = v
+		}
+	}
+	c.State.Hints.SkipHours = append([]int(nil), sub.State.Hints.SkipHours...)
+	c.State.Hints.SkipDays = append([]time.Weekday(nil), sub.State.Hints.SkipDays...)
+	return &c
+}
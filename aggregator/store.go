@@ -0,0 +1,64 @@
+// Package aggregator keeps a set of feed subscriptions up to date.
+// A Store holds the subscriptions, the state of their last fetches
+// and the items already seen, and an Aggregator polls them with a
+// scheduler.Scheduler and reports the items that are new.
+package aggregator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mmcdole/gofeed/health"
+	"github.com/mmcdole/gofeed/scheduler"
+)
+
+// ErrNotFound is returned for feeds which
+// aren't subscribed to.
+var ErrNotFound = errors.New("subscription not found")
+
+// Subscription is a feed which is being followed.
+type Subscription struct {
+	URL   string    `json:"url"`
+	Title string    `json:"title,omitempty"`
+	Added time.Time `json:"added"`
+	// State is the fetch state of the feed: its HTTP
+	// validators, the times of its last polls and
+	// the number of polls in a row which failed.
+	State scheduler.State `json:"state"`
+	// LastError is the error of the last
+	// poll if it failed.
+	LastError string `json:"lastError,omitempty"`
+	// Health tracks failures, moves and staleness of the
+	// feed across polls.  See health.Health.Assess.
+	Health health.Health `json:"health"`
+}
+
+// Store persists subscriptions and the items seen in them.
+// Implementations must be safe for concurrent use.  Values
+// passed to and returned from a Store aren't shared with it.
+type Store interface {
+	// Subscriptions returns all subscriptions,
+	// ordered by URL.
+	Subscriptions() ([]*Subscription, error)
+	// Subscription returns the subscription to a
+	// feed, or ErrNotFound.
+	Subscription(feedURL string) (*Subscription, error)
+	// Add saves a subscription unless the feed is already
+	// subscribed to, and reports whether it was saved.
+	Add(sub *Subscription) (bool, error)
+	// Save adds or replaces a subscription.
+	Save(sub *Subscription) error
+	// Delete removes a subscription and its seen items.
+	// Deleting a missing subscription isn't an error.
+	Delete(feedURL string) error
+	// MarkSeen records that items of a feed were seen.
+	MarkSeen(feedURL string, ids ...string) error
+	// Seen reports whether an item of a feed was seen.
+	Seen(feedURL, id string) (bool, error)
+	// PruneSeen notes that the seen items of a feed listed in
+	// ids are still in it, and forgets the others which haven't
+	// been in it since before the given time.
+	PruneSeen(feedURL string, ids []string, before time.Time) error
+	// Flush saves changes which the store holds back, if any.
+	Flush() error
+}
@@ -0,0 +1,155 @@
+package aggregator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed/aggregator"
+	"github.com/mmcdole/gofeed/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStore(t *testing.T, store aggregator.Store) {
+	subs, err := store.Subscriptions()
+	assert.Nil(t, err)
+	assert.Empty(t, subs)
+
+	_, err = store.Subscription("http://b.example.org/feed")
+	assert.Equal(t, aggregator.ErrNotFound, err)
+
+	added := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	b := &aggregator.Subscription{
+		URL:   "http://b.example.org/feed",
+		Added: added,
+		State: scheduler.State{
+			ETag:     `"v1"`,
+			Failures: 2,
+			Items:    map[string]bool{"guid:1": true},
+		},
+	}
+	ok, err := store.Add(b)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = store.Add(&aggregator.Subscription{URL: b.URL, Title: "again"})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, store.Save(&aggregator.Subscription{URL: "http://a.example.org/feed"}))
+
+	// The store keeps its own copy
+	b.State.Items["guid:2"] = true
+	b.Title = "changed"
+
+	got, err := store.Subscription(b.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "", got.Title)
+	assert.Equal(t, `"v1"`, got.State.ETag)
+	assert.Equal(t, 2, got.State.Failures)
+	assert.Equal(t, map[string]bool{"guid:1": true}, got.State.Items)
+	assert.True(t, added.Equal(got.Added))
+
+	subs, err = store.Subscriptions()
+	assert.Nil(t, err)
+	if assert.Len(t, subs, 2) {
+		assert.Equal(t, "http://a.example.org/feed", subs[0].URL)
+		assert.Equal(t, "http://b.example.org/feed", subs[1].URL)
+	}
+
+	assert.Nil(t, store.MarkSeen(b.URL, "guid:1", "guid:2"))
+	seen, err := store.Seen(b.URL, "guid:2")
+	assert.Nil(t, err)
+	assert.True(t, seen)
+	seen, _ = store.Seen(b.URL, "guid:3")
+	assert.False(t, seen)
+	seen, _ = store.Seen("http://a.example.org/feed", "guid:1")
+	assert.False(t, seen)
+
+	// Items which dropped out of the feed are
+	// forgotten once they're old enough
+	assert.Nil(t, store.PruneSeen(b.URL, []string{"guid:2", "guid:3"}, time.Now().Add(-time.Hour)))
+	seen, _ = store.Seen(b.URL, "guid:1")
+	assert.True(t, seen)
+	assert.Nil(t, store.PruneSeen(b.URL, []string{"guid:2", "guid:3"}, time.Now().Add(time.Hour)))
+	seen, _ = store.Seen(b.URL, "guid:1")
+	assert.False(t, seen)
+	seen, _ = store.Seen(b.URL, "guid:3")
+	assert.False(t, seen)
+	assert.Nil(t, store.MarkSeen(b.URL, "guid:1"))
+
+	assert.Nil(t, store.Delete(b.URL))
+	assert.Nil(t, store.Delete(b.URL))
+	_, err = store.Subscription(b.URL)
+	assert.Equal(t, aggregator.ErrNotFound, err)
+	seen, _ = store.Seen(b.URL, "guid:1")
+	assert.False(t, seen)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, aggregator.NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	store, err := aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+	testStore(t, store)
+
+	// Changes are persisted
+	assert.Nil(t, store.Save(&aggregator.Subscription{URL: "http://c.example.org/feed", Title: "C"}))
+	assert.Nil(t, store.MarkSeen("http://c.example.org/feed", "guid:1"))
+	assert.Nil(t, store.Flush())
+
+	reopened, err := aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+	subs, err := reopened.Subscriptions()
+	assert.Nil(t, err)
+	if assert.Len(t, subs, 2) {
+		assert.Equal(t, "C", subs[1].Title)
+	}
+	seen, _ := reopened.Seen("http://c.example.org/feed", "guid:1")
+	assert.True(t, seen)
+
+	// No temporary files are left behind
+	files, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, files, 1)
+}
+
+func TestFileStore_WriteDelay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	store, err := aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+	store.WriteDelay = 50 * time.Millisecond
+
+	// Changes are saved together once the delay is over
+	assert.Nil(t, store.Save(&aggregator.Subscription{URL: "http://a.example.org/feed"}))
+	assert.Nil(t, store.MarkSeen("http://a.example.org/feed", "guid:1"))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	reopened, err := aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+	seen, _ := reopened.Seen("http://a.example.org/feed", "guid:1")
+	assert.True(t, seen)
+
+	// Flush saves them at once
+	store.WriteDelay = time.Hour
+	assert.Nil(t, store.Delete("http://a.example.org/feed"))
+	assert.Nil(t, store.Flush())
+	reopened, err = aggregator.OpenFileStore(path)
+	assert.Nil(t, err)
+	subs, _ := reopened.Subscriptions()
+	assert.Empty(t, subs)
+}
+
+func TestOpenFileStore_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	os.WriteFile(path, []byte("{not json"), 0644)
+
+	_, err := aggregator.OpenFileStore(path)
+	assert.NotNil(t, err)
+}
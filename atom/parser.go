@@ -1,11 +1,13 @@
 package atom
 
 import (
+	"context"
 	"encoding/base64"
 	"io"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed/dates"
 	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/gofeed/internal/shared"
 	xpp "github.com/mmcdole/goxpp"
@@ -24,11 +26,29 @@ var (
 )
 
 // Parser is an Atom Parser
-type Parser struct{}
+type Parser struct {
+	// DateParser parses the feed's dates.  A nil
+	// DateParser uses a dates.StandardParser.
+	DateParser dates.Parser
+	// StripIllegalChars removes characters which aren't allowed
+	// in XML from documents in encodings other than UTF-8 as
+	// they are converted.
+	StripIllegalChars bool
+
+	dates dates.Parser
+}
 
 // Parse parses an xml feed into an atom.Feed
 func (ap *Parser) Parse(feed io.Reader) (*Feed, error) {
-	p := xpp.NewXMLPullParser(feed, false, shared.NewReaderLabel)
+	return ap.ParseWithContext(feed, context.Background())
+}
+
+// ParseWithContext is like Parse but stops with the error of
+// the context once it's done.  The context is checked as the
+// document is read.
+func (ap *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Feed, error) {
+	ap = ap.forFeed()
+	p := xpp.NewXMLPullParser(shared.NewContextReader(ctx, feed), false, shared.CharsetReader(ap.StripIllegalChars))
 
 	_, err := shared.FindRoot(p)
 	if err != nil {
@@ -38,6 +58,14 @@ func (ap *Parser) Parse(feed io.Reader) (*Feed, error) {
 	return ap.parseRoot(p)
 }
 
+// forFeed returns a copy of the parser holding
+// the date parser for a single feed.
+func (ap *Parser) forFeed() *Parser {
+	c := *ap
+	c.dates = dates.ForFeed(ap.DateParser)
+	return &c
+}
+
 func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 	if err := p.Expect(xpp.StartTag, "feed"); err != nil {
 		return nil, err
@@ -93,7 +121,7 @@ func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 					return nil, err
 				}
 				atom.Updated = result
-				date, err := shared.ParseDate(result)
+				date, err := ap.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					atom.UpdatedParsed = &utcDate
@@ -266,7 +294,7 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 					return nil, err
 				}
 				entry.Updated = result
-				date, err := shared.ParseDate(result)
+				date, err := ap.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					entry.UpdatedParsed = &utcDate
@@ -302,7 +330,7 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 					return nil, err
 				}
 				entry.Published = result
-				date, err := shared.ParseDate(result)
+				date, err := ap.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					entry.PublishedParsed = &utcDate
@@ -402,7 +430,7 @@ func (ap *Parser) parseSource(p *xpp.XMLPullParser) (*Source, error) {
 					return nil, err
 				}
 				source.Updated = result
-				date, err := shared.ParseDate(result)
+				date, err := ap.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					source.UpdatedParsed = &utcDate
@@ -2,7 +2,9 @@ package atom_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -48,3 +50,16 @@ func TestParser_Parse(t *testing.T) {
 }
 
 // TODO: Examples
+
+func TestParser_ParseWithContext(t *testing.T) {
+	fp := &atom.Parser{}
+	feed, err := fp.ParseWithContext(strings.NewReader(`<feed xmlns="http://www.w3.org/2005/Atom"><title>Title</title></feed>`), context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", feed.Title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	feed, err = fp.ParseWithContext(strings.NewReader(`<feed xmlns="http://www.w3.org/2005/Atom"><title>Title</title></feed>`), ctx)
+	assert.Nil(t, feed)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -1,7 +1,6 @@
-package shared
+package dates
 
 import (
-	"fmt"
 	"strings"
 	"time"
 )
@@ -187,37 +186,75 @@ var dateFormatsWithNamedZone = []string{
 	"01/02/2006 15:04:05 MST",
 }
 
-// ParseDate parses a given date string using a large
-// list of commonly found feed date formats.
-func ParseDate(ds string) (t time.Time, err error) {
-	d := strings.TrimSpace(ds)
-	if d == "" {
-		return t, fmt.Errorf("Date string is empty")
+// Layouts tried after localized month and day names have been
+// translated to English. Each is expanded with the abbreviated
+// forms of the day and month names by expandLayouts.
+var localizedDateFormats = expandLayouts([]string{
+	"Monday, 2 January 2006 15:04:05 -0700",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, 2 January 2006 15:04",
+	"Monday, 2 January 2006, 15:04",
+	"Monday, 2 January 2006",
+	"Monday 2 January 2006 15:04:05 -0700",
+	"Monday 2 January 2006 15:04:05",
+	"Monday 2 January 2006 15:04",
+	"Monday 2 January 2006",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04:05",
+	"2 January 2006 15:04",
+	"2 January 2006, 15:04:05",
+	"2 January 2006, 15:04",
+	"2 January 2006",
+	"January 2, 2006 15:04",
+	"January 2 2006 15:04",
+	"January 2 2006",
+})
+
+// expandLayouts returns layouts along with every variant
+// using abbreviated day and month names.
+func expandLayouts(layouts []string) []string {
+	result := []string{}
+	for _, l := range layouts {
+		short := strings.Replace(l, "Monday", "Mon", 1)
+		result = append(result,
+			l,
+			short,
+			strings.Replace(l, "January", "Jan", 1),
+			strings.Replace(short, "January", "Jan", 1))
 	}
-	for _, f := range dateFormats {
-		if t, err = time.Parse(f, d); err == nil {
-			return
-		}
+	return result
+}
+
+// layout is a single date layout that Parser
+// can try and remember.
+type layout struct {
+	format      string
+	localized   bool
+	numericZone bool
+}
+
+func newLayout(format string, localized bool) *layout {
+	return &layout{
+		format:      format,
+		localized:   localized,
+		numericZone: strings.Contains(format, "-07") || strings.Contains(format, "Z07"),
 	}
-	for _, f := range dateFormatsWithNamedZone {
-		t, err = time.Parse(f, d)
-		if err != nil {
-			continue
-		}
+}
 
-		// This is a format match! Now try to load the timezone name
-		loc, err := time.LoadLocation(t.Location().String())
-		if err != nil {
-			// We couldn't load the TZ name. Just use UTC instead...
-			return t, nil
-		}
+var (
+	englishLayouts   []*layout
+	localizedLayouts []*layout
+)
 
-		if t, err = time.ParseInLocation(f, ds, loc); err == nil {
-			return t, nil
+func init() {
+	// Layouts with named zones come last as the
+	// abbreviation is only a guess at the offset.
+	for _, formats := range [][]string{dateFormats, dateFormatsWithNamedZone} {
+		for _, f := range formats {
+			englishLayouts = append(englishLayouts, newLayout(f, false))
 		}
-		// This should not be reachable
 	}
-
-	err = fmt.Errorf("Failed to parse date: %s", ds)
-	return
+	for _, f := range localizedDateFormats {
+		localizedLayouts = append(localizedLayouts, newLayout(f, true))
+	}
 }
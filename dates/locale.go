@@ -0,0 +1,326 @@
+package dates
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Locale lists the month and day names of a language so that
+// StandardParser can translate them to English before parsing.
+//
+// The first name of each month and day is its full name.  Later
+// names are treated as abbreviations unless they are longer than
+// four letters (such as inflected forms of the full name).  Names
+// are matched case-insensitively and may be followed by a period.
+type Locale struct {
+	Name string
+
+	// Months holds the names of January through December.
+	Months [12][]string
+
+	// Days holds the names of Sunday through Saturday.
+	Days [7][]string
+
+	// Skip lists words which are dropped from dates, such
+	// as the "de" in "2 de enero de 2006".
+	Skip []string
+
+	// Layouts are additional time.Parse layouts for the
+	// locale, tried after the names have been translated.
+	Layouts []string
+}
+
+type word struct {
+	month     time.Month
+	monthFull bool
+	day       time.Weekday
+	hasDay    bool
+	dayFull   bool
+	skip      bool
+}
+
+// english returns the English replacement for the word.  Words
+// which name both a day and a month (such as Spanish "mar") are
+// read as the day if they start the date.
+func (w *word) english(first bool) string {
+	if w.hasDay && (first || w.month == 0) {
+		return shorten(w.day.String(), w.dayFull)
+	}
+	if w.month != 0 {
+		return shorten(w.month.String(), w.monthFull)
+	}
+	return ""
+}
+
+func shorten(name string, full bool) string {
+	if full {
+		return name
+	}
+	return name[:3]
+}
+
+func isFullName(i int, name string) bool {
+	return i == 0 || utf8.RuneCountInString(name) > 4
+}
+
+func (l *Locale) addWords(words map[string]*word) {
+	lookup := func(name string) *word {
+		name = strings.ToLower(name)
+		w, ok := words[name]
+		if !ok {
+			w = &word{}
+			words[name] = w
+		}
+		return w
+	}
+
+	for m, names := range l.Months {
+		for i, name := range names {
+			if w := lookup(name); w.month == 0 {
+				w.month = time.Month(m + 1)
+				w.monthFull = isFullName(i, name)
+			}
+		}
+	}
+	for d, names := range l.Days {
+		for i, name := range names {
+			if w := lookup(name); !w.hasDay {
+				w.day = time.Weekday(d)
+				w.hasDay = true
+				w.dayFull = isFullName(i, name)
+			}
+		}
+	}
+	for _, name := range l.Skip {
+		lookup(name).skip = true
+	}
+}
+
+// Built-in locales.
+var (
+	French = &Locale{
+		Name: "fr",
+		Months: [12][]string{
+			{"janvier", "janv"},
+			{"février", "févr", "fevrier", "fevr"},
+			{"mars"},
+			{"avril", "avr"},
+			{"mai"},
+			{"juin"},
+			{"juillet", "juil"},
+			{"août", "aout"},
+			{"septembre", "sept"},
+			{"octobre", "oct"},
+			{"novembre", "nov"},
+			{"décembre", "déc", "decembre", "dec"},
+		},
+		Days: [7][]string{
+			{"dimanche", "dim"},
+			{"lundi", "lun"},
+			{"mardi", "mar"},
+			{"mercredi", "mer"},
+			{"jeudi", "jeu"},
+			{"vendredi", "ven"},
+			{"samedi", "sam"},
+		},
+		Skip: []string{"à", "le"},
+	}
+
+	German = &Locale{
+		Name: "de",
+		Months: [12][]string{
+			{"Januar", "Jan", "Jänner", "Jän"},
+			{"Februar", "Feb"},
+			{"März", "Mär", "Maerz", "Mrz"},
+			{"April", "Apr"},
+			{"Mai"},
+			{"Juni", "Jun"},
+			{"Juli", "Jul"},
+			{"August", "Aug"},
+			{"September", "Sep", "Sept"},
+			{"Oktober", "Okt"},
+			{"November", "Nov"},
+			{"Dezember", "Dez"},
+		},
+		Days: [7][]string{
+			{"Sonntag", "So"},
+			{"Montag", "Mo"},
+			{"Dienstag", "Di"},
+			{"Mittwoch", "Mi"},
+			{"Donnerstag", "Do"},
+			{"Freitag", "Fr"},
+			{"Samstag", "Sa", "Sonnabend"},
+		},
+		Skip: []string{"um", "Uhr"},
+	}
+
+	Spanish = &Locale{
+		Name: "es",
+		Months: [12][]string{
+			{"enero", "ene"},
+			{"febrero", "feb"},
+			{"marzo", "mar"},
+			{"abril", "abr"},
+			{"mayo", "may"},
+			{"junio", "jun"},
+			{"julio", "jul"},
+			{"agosto", "ago"},
+			{"septiembre", "sep", "sept", "setiembre"},
+			{"octubre", "oct"},
+			{"noviembre", "nov"},
+			{"diciembre", "dic"},
+		},
+		Days: [7][]string{
+			{"domingo", "dom"},
+			{"lunes", "lun"},
+			{"martes", "mar"},
+			{"miércoles", "mié", "miercoles", "mie"},
+			{"jueves", "jue"},
+			{"viernes", "vie"},
+			{"sábado", "sáb", "sabado", "sab"},
+		},
+		Skip: []string{"de", "del", "a", "la", "las"},
+	}
+
+	Portuguese = &Locale{
+		Name: "pt",
+		Months: [12][]string{
+			{"janeiro", "jan"},
+			{"fevereiro", "fev"},
+			{"março", "mar", "marco"},
+			{"abril", "abr"},
+			{"maio", "mai"},
+			{"junho", "jun"},
+			{"julho", "jul"},
+			{"agosto", "ago"},
+			{"setembro", "set"},
+			{"outubro", "out"},
+			{"novembro", "nov"},
+			{"dezembro", "dez"},
+		},
+		Days: [7][]string{
+			{"domingo", "dom"},
+			{"segunda-feira", "segunda", "seg"},
+			{"terça-feira", "terça", "terca-feira", "terca", "ter"},
+			{"quarta-feira", "quarta", "qua"},
+			{"quinta-feira", "quinta", "qui"},
+			{"sexta-feira", "sexta", "sex"},
+			{"sábado", "sáb", "sabado", "sab"},
+		},
+		Skip: []string{"de", "às", "as", "à", "feira"},
+	}
+
+	Italian = &Locale{
+		Name: "it",
+		Months: [12][]string{
+			{"gennaio", "gen"},
+			{"febbraio", "feb"},
+			{"marzo", "mar"},
+			{"aprile", "apr"},
+			{"maggio", "mag"},
+			{"giugno", "giu"},
+			{"luglio", "lug"},
+			{"agosto", "ago"},
+			{"settembre", "set"},
+			{"ottobre", "ott"},
+			{"novembre", "nov"},
+			{"dicembre", "dic"},
+		},
+		Days: [7][]string{
+			{"domenica", "dom"},
+			{"lunedì", "lun", "lunedi"},
+			{"martedì", "mar", "martedi"},
+			{"mercoledì", "mer", "mercoledi"},
+			{"giovedì", "gio", "giovedi"},
+			{"venerdì", "ven", "venerdi"},
+			{"sabato", "sab"},
+		},
+		Skip: []string{"alle", "ore", "il"},
+	}
+
+	Dutch = &Locale{
+		Name: "nl",
+		Months: [12][]string{
+			{"januari", "jan"},
+			{"februari", "feb"},
+			{"maart", "mrt"},
+			{"april", "apr"},
+			{"mei"},
+			{"juni", "jun"},
+			{"juli", "jul"},
+			{"augustus", "aug"},
+			{"september", "sep", "sept"},
+			{"oktober", "okt"},
+			{"november", "nov"},
+			{"december", "dec"},
+		},
+		Days: [7][]string{
+			{"zondag", "zo"},
+			{"maandag", "ma"},
+			{"dinsdag", "di"},
+			{"woensdag", "wo"},
+			{"donderdag", "do"},
+			{"vrijdag", "vr"},
+			{"zaterdag", "za"},
+		},
+		Skip: []string{"om"},
+	}
+
+	Russian = &Locale{
+		Name: "ru",
+		Months: [12][]string{
+			{"январь", "января", "янв"},
+			{"февраль", "февраля", "фев"},
+			{"март", "марта", "мар"},
+			{"апрель", "апреля", "апр"},
+			{"май", "мая"},
+			{"июнь", "июня", "июн"},
+			{"июль", "июля", "июл"},
+			{"август", "августа", "авг"},
+			{"сентябрь", "сентября", "сен", "сент"},
+			{"октябрь", "октября", "окт"},
+			{"ноябрь", "ноября", "ноя", "нояб"},
+			{"декабрь", "декабря", "дек"},
+		},
+		Days: [7][]string{
+			{"воскресенье", "вс"},
+			{"понедельник", "пн"},
+			{"вторник", "вт"},
+			{"среда", "ср"},
+			{"четверг", "чт"},
+			{"пятница", "пт"},
+			{"суббота", "сб"},
+		},
+		Skip: []string{"г", "года", "в"},
+	}
+
+	Japanese = &Locale{
+		Name: "ja",
+		// Weekdays are redundant next to the numeric
+		// dates, so they are dropped rather than translated.
+		Skip: []string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		Layouts: []string{
+			"2006年1月2日 15:04:05",
+			"2006年1月2日 15:04",
+			"2006年1月2日 15時04分",
+			"2006年1月2日",
+			"2006/01/02 15:04:05",
+			"2006/01/02 15:04",
+			"2006/01/02",
+		},
+	}
+
+	// DefaultLocales are the locales used by a
+	// StandardParser without Locales.
+	DefaultLocales = []*Locale{
+		French,
+		German,
+		Spanish,
+		Portuguese,
+		Italian,
+		Dutch,
+		Russian,
+		Japanese,
+	}
+)
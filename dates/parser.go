@@ -0,0 +1,241 @@
+// Package dates parses the many date formats found in
+// RSS, Atom and JSON feeds.
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Parser parses the date strings found in feeds.
+//
+// Parsers which keep state for the duration of a single
+// feed can implement a ForFeed() Parser method; the feed
+// parsers call it once per feed (see ForFeed).
+type Parser interface {
+	Parse(value string) (time.Time, error)
+}
+
+// ForFeed returns the Parser to use for the dates of a single
+// feed.  A nil p returns a new StandardParser.  If p has a
+// ForFeed() Parser method its result is returned, otherwise p
+// itself is.
+func ForFeed(p Parser) Parser {
+	if p == nil {
+		return NewParser()
+	}
+	if fp, ok := p.(interface{ ForFeed() Parser }); ok {
+		return fp.ForFeed()
+	}
+	return p
+}
+
+// Parse parses a date string with a default StandardParser.
+func Parse(value string) (time.Time, error) {
+	return defaultParser.Parse(value)
+}
+
+var defaultParser = NewParser()
+
+// StandardParser is the default Parser.  It tries a large list of
+// commonly found English date formats, and falls back to
+// translating month and day names of its Locales to English.
+//
+// The layout which parsed the last date is remembered and tried
+// first on the next call, since all dates in a feed are usually
+// written the same way.  A StandardParser is safe for concurrent
+// use, but callers should use ForFeed to get a parser per feed
+// so that feeds don't evict each other's layouts.
+type StandardParser struct {
+	// Locales are the languages whose month and day names
+	// are recognized.  A nil slice means DefaultLocales.
+	Locales []*Locale
+
+	// Location is the time zone of dates which don't
+	// specify one.  A nil Location means UTC.
+	Location *time.Location
+
+	// Zones overrides the offsets, in seconds east of UTC,
+	// of time zone abbreviations (see ZoneOffset).
+	Zones map[string]int
+
+	once  sync.Once
+	words map[string]*word
+	extra []*layout
+
+	mu   sync.Mutex
+	last *layout
+}
+
+// NewParser creates a StandardParser using DefaultLocales.
+func NewParser() *StandardParser {
+	return &StandardParser{}
+}
+
+// ForFeed returns a new StandardParser with the same
+// configuration and an empty layout cache.
+func (p *StandardParser) ForFeed() Parser {
+	p.once.Do(p.init)
+	fp := &StandardParser{
+		Locales:  p.Locales,
+		Location: p.Location,
+		Zones:    p.Zones,
+		words:    p.words,
+		extra:    p.extra,
+	}
+	fp.once.Do(func() {})
+	return fp
+}
+
+func (p *StandardParser) init() {
+	locales := p.Locales
+	if locales == nil {
+		locales = DefaultLocales
+	}
+	p.words = map[string]*word{}
+	for _, l := range locales {
+		l.addWords(p.words)
+		for _, f := range l.Layouts {
+			p.extra = append(p.extra, newLayout(f, true))
+		}
+	}
+}
+
+// Parse parses a date string.
+func (p *StandardParser) Parse(value string) (time.Time, error) {
+	d := strings.TrimSpace(value)
+	if d == "" {
+		return time.Time{}, fmt.Errorf("Date string is empty")
+	}
+	p.once.Do(p.init)
+
+	p.mu.Lock()
+	last := p.last
+	p.mu.Unlock()
+
+	translated := ""
+	if last != nil {
+		s := d
+		if last.localized {
+			translated = p.translate(d)
+			s = translated
+		}
+		if t, ok := p.parseLayout(last, s); ok {
+			return t, nil
+		}
+	}
+
+	for _, l := range englishLayouts {
+		if t, ok := p.parseLayout(l, d); ok {
+			p.remember(l)
+			return t, nil
+		}
+	}
+
+	if translated == "" {
+		translated = p.translate(d)
+	}
+	for _, layouts := range [][]*layout{p.extra, localizedLayouts} {
+		for _, l := range layouts {
+			if t, ok := p.parseLayout(l, translated); ok {
+				p.remember(l)
+				return t, nil
+			}
+		}
+	}
+	if translated != d {
+		for _, l := range englishLayouts {
+			if t, ok := p.parseLayout(l, translated); ok {
+				p.remember(newLayout(l.format, true))
+				return t, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Failed to parse date: %s", value)
+}
+
+func (p *StandardParser) remember(l *layout) {
+	p.mu.Lock()
+	p.last = l
+	p.mu.Unlock()
+}
+
+// translate replaces the localized month and day names in d with
+// their English equivalents and removes the words which the
+// English layouts have no place for.
+func (p *StandardParser) translate(d string) string {
+	rs := []rune(d)
+	var b strings.Builder
+	first := true
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) ||
+				rs[j] == '-' && j+1 < len(rs) && unicode.IsLetter(rs[j+1])) {
+				j++
+			}
+			w := string(rs[i:j])
+			if e, ok := p.words[strings.ToLower(w)]; ok {
+				// Abbreviations are often followed by a period
+				if j < len(rs) && rs[j] == '.' {
+					j++
+				}
+				w = e.english(first)
+			}
+			b.WriteString(w)
+			first = false
+			i = j
+
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && unicode.IsDigit(rs[j]) {
+				j++
+			}
+			b.WriteString(string(rs[i:j]))
+			// Ordinal day numbers such as "2. Januar"
+			if j+1 < len(rs) && rs[j] == '.' && unicode.IsSpace(rs[j+1]) {
+				j++
+			}
+			first = false
+			i = j
+
+		case r == '(' || r == '（':
+			// Drop weekday annotations such as "(月)"
+			if j := parenthesizedName(rs, i); j > i {
+				b.WriteRune(' ')
+				i = j
+				continue
+			}
+			b.WriteRune(r)
+			i++
+
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+
+	s := strings.Join(strings.Fields(b.String()), " ")
+	return strings.Replace(s, " ,", ",", -1)
+}
+
+// parenthesizedName returns the index after a parenthesized group
+// starting at i which holds nothing but non-ASCII letters, or i if
+// there is no such group.
+func parenthesizedName(rs []rune, i int) int {
+	j := i + 1
+	for j < len(rs) && unicode.IsLetter(rs[j]) && rs[j] > unicode.MaxASCII {
+		j++
+	}
+	if j == i+1 || j == len(rs) || (rs[j] != ')' && rs[j] != '）') {
+		return i
+	}
+	return j + 1
+}
@@ -0,0 +1,162 @@
+package dates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed/dates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardParser_Parse(t *testing.T) {
+	jan2 := time.Date(2006, time.January, 2, 15, 4, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"Mon, 02 Jan 2006 15:04:00 +0000", jan2},
+		{"2006-01-02T15:04:00Z", jan2},
+		{"  January 2, 2006 15:04:00  ", jan2},
+
+		{"lundi 2 janvier 2006 à 15:04", jan2},
+		{"lun., 2 janv. 2006 15:04", jan2},
+		{"Montag, 2. Januar 2006 15:04 Uhr", jan2},
+		{"Mo, 02 Jan 2006 15:04:00 +0000", jan2},
+		{"lunes, 2 de enero de 2006 15:04", jan2},
+		{"segunda-feira, 2 de janeiro de 2006 às 15:04", jan2},
+		{"lunedì 2 gennaio 2006 alle 15:04", jan2},
+		{"maandag 2 januari 2006 om 15:04", jan2},
+		{"2 января 2006 г., 15:04", jan2},
+		{"понедельник, 2 января 2006 в 15:04", jan2},
+		{"2006年1月2日(月) 15:04", jan2},
+		{"2006年1月2日 月曜日 15時04分", jan2},
+
+		// "mar" is both Tuesday and March in Spanish
+		{"mar, 7 mar 2006 15:04:00 +0000", time.Date(2006, time.March, 7, 15, 4, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		date, err := dates.NewParser().Parse(test.value)
+		if assert.Nil(t, err, "failed to parse %q", test.value) {
+			assert.True(t, test.want.Equal(date), "%q was parsed as %s instead of %s", test.value, date, test.want)
+		}
+	}
+}
+
+func TestStandardParser_ParseInvalid(t *testing.T) {
+	p := dates.NewParser()
+	for _, value := range []string{"", "   ", "not a date", "32 foo 2006"} {
+		_, err := p.Parse(value)
+		assert.NotNil(t, err, "%q should not parse", value)
+	}
+}
+
+func TestStandardParser_Locales(t *testing.T) {
+	p := &dates.StandardParser{Locales: []*dates.Locale{dates.German}}
+
+	_, err := p.Parse("2 janvier 2006")
+	assert.NotNil(t, err)
+
+	date, err := p.Parse("2. Januar 2006")
+	assert.Nil(t, err)
+	assert.Equal(t, time.January, date.Month())
+}
+
+func TestStandardParser_CachedLayout(t *testing.T) {
+	p := dates.ForFeed(dates.NewParser())
+
+	// The remembered layout must not prevent other
+	// layouts from being tried when it stops matching.
+	values := []string{
+		"2 janvier 2006 15:04",
+		"3 février 2006 10:00",
+		"Mon, 06 Mar 2006 08:00:00 +0000",
+		"7 avril 2006 09:30",
+	}
+	for _, value := range values {
+		_, err := p.Parse(value)
+		assert.Nil(t, err, "failed to parse %q", value)
+	}
+}
+
+type fixedParser struct{}
+
+func (fixedParser) Parse(value string) (time.Time, error) {
+	return time.Unix(0, 0), nil
+}
+
+func TestForFeed(t *testing.T) {
+	assert.IsType(t, &dates.StandardParser{}, dates.ForFeed(nil))
+
+	p := dates.NewParser()
+	assert.NotSame(t, p, dates.ForFeed(p))
+
+	assert.Equal(t, fixedParser{}, dates.ForFeed(fixedParser{}))
+}
+
+func TestStandardParser_ZoneAbbreviations(t *testing.T) {
+	tests := []struct {
+		value  string
+		offset int
+	}{
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0},
+		{"Mon, 02 Jan 2006 15:04:05 UTC", 0},
+		{"Mon, 02 Jan 2006 15:04:05 EST", -5 * 60 * 60},
+		{"Mon, 02 Jan 2006 15:04:05 PDT", -7 * 60 * 60},
+		{"Mon, 02 Jan 2006 15:04:05 CEST", 2 * 60 * 60},
+		{"Mon, 02 Jan 2006 15:04:05 IST", 5*60*60 + 30*60},
+		{"02 Jan 06 15:04 AEST", 10 * 60 * 60},
+		{"Mon, 02 Jan 2006 15:04:05 -0300 EST", -3 * 60 * 60},
+		{"Mon, 02 Jan 2006 15:04:05 XYZ", 0},
+	}
+
+	for _, test := range tests {
+		date, err := dates.NewParser().Parse(test.value)
+		if assert.Nil(t, err, "failed to parse %q", test.value) {
+			_, offset := date.Zone()
+			assert.Equal(t, test.offset, offset, "wrong offset for %q", test.value)
+			assert.Equal(t, 15, date.Hour(), "wrong hour for %q", test.value)
+		}
+	}
+}
+
+func TestStandardParser_ZoneAmbiguity(t *testing.T) {
+	value := "Mon, 02 Jan 2006 15:04:05 CST"
+
+	date, err := dates.NewParser().Parse(value)
+	assert.Nil(t, err)
+	_, offset := date.Zone()
+	assert.Equal(t, -6*60*60, offset)
+
+	// A location which uses the abbreviation decides its meaning
+	p := &dates.StandardParser{Location: time.FixedZone("CST", 8*60*60)}
+	date, err = p.Parse(value)
+	assert.Nil(t, err)
+	_, offset = date.Zone()
+	assert.Equal(t, 8*60*60, offset)
+
+	p = &dates.StandardParser{Zones: map[string]int{"CST": -5 * 60 * 60}}
+	date, err = p.Parse(value)
+	assert.Nil(t, err)
+	_, offset = date.Zone()
+	assert.Equal(t, -5*60*60, offset)
+}
+
+func TestStandardParser_Location(t *testing.T) {
+	loc := time.FixedZone("Test", 3*60*60)
+	p := &dates.StandardParser{Location: loc}
+
+	date, err := p.Parse("2006-01-02 15:04:05")
+	assert.Nil(t, err)
+	assert.Equal(t, loc, date.Location())
+	assert.Equal(t, 12, date.UTC().Hour())
+
+	// Explicit offsets are unaffected
+	date, err = p.Parse("2006-01-02T15:04:05Z")
+	assert.Nil(t, err)
+	assert.Equal(t, 15, date.UTC().Hour())
+
+	date, err = dates.NewParser().Parse("2006-01-02 15:04:05")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, date.Location())
+}
@@ -0,0 +1,140 @@
+package dates
+
+import (
+	"time"
+)
+
+const hour = 60 * 60
+
+// zoneOffsets maps time zone abbreviations to their offset in
+// seconds east of UTC.
+//
+// Several abbreviations are used by more than one zone.  These
+// map to the zone most often found in feeds, with the others
+// noted alongside.  Parsers that need a different reading can
+// set StandardParser.Location to a location which uses the
+// abbreviation, or override it with StandardParser.Zones.
+var zoneOffsets = map[string]int{
+	"UT":  0,
+	"UTC": 0,
+	"GMT": 0,
+	"Z":   0,
+
+	// North America
+	"EST":  -5 * hour,
+	"EDT":  -4 * hour,
+	"CST":  -6 * hour, // also China Standard Time (+8), Cuba Standard Time (-5)
+	"CDT":  -5 * hour, // also Cuba Daylight Time (-4)
+	"MST":  -7 * hour,
+	"MDT":  -6 * hour,
+	"PST":  -8 * hour, // also Philippine Standard Time (+8)
+	"PDT":  -7 * hour,
+	"AKST": -9 * hour,
+	"AKDT": -8 * hour,
+	"HST":  -10 * hour,
+	"HDT":  -9 * hour,
+	"AST":  -4 * hour, // also Arabia Standard Time (+3)
+	"ADT":  -3 * hour,
+	"NST":  -3*hour - 30*60,
+	"NDT":  -2*hour - 30*60,
+
+	// South America
+	"BRT":  -3 * hour,
+	"BRST": -2 * hour,
+	"ART":  -3 * hour,
+	"CLT":  -4 * hour,
+	"CLST": -3 * hour,
+
+	// Europe
+	"WET":  0,
+	"WEST": 1 * hour,
+	"BST":  1 * hour, // also Bangladesh Standard Time (+6)
+	"CET":  1 * hour,
+	"CEST": 2 * hour,
+	"MET":  1 * hour,
+	"MEST": 2 * hour,
+	"EET":  2 * hour,
+	"EEST": 3 * hour,
+	"MSK":  3 * hour,
+	"TRT":  3 * hour,
+
+	// Africa and the Middle East
+	"WAT":  1 * hour,
+	"CAT":  2 * hour,
+	"SAST": 2 * hour,
+	"EAT":  3 * hour,
+	"IDT":  3 * hour,
+	"GST":  4 * hour, // Gulf; also South Georgia Time (-2)
+
+	// Asia and Oceania
+	"PKT":  5 * hour,
+	"IST":  5*hour + 30*60, // India; also Irish Standard Time (+1), Israel Standard Time (+2)
+	"NPT":  5*hour + 45*60,
+	"ICT":  7 * hour,
+	"WIB":  7 * hour,
+	"HKT":  8 * hour,
+	"SGT":  8 * hour,
+	"AWST": 8 * hour,
+	"JST":  9 * hour,
+	"KST":  9 * hour,
+	"ACST": 9*hour + 30*60,
+	"ACDT": 10*hour + 30*60,
+	"AEST": 10 * hour,
+	"AEDT": 11 * hour,
+	"NZST": 12 * hour,
+	"NZDT": 13 * hour,
+}
+
+// ZoneOffset returns the offset in seconds east of UTC of a
+// time zone abbreviation such as "EST" or "CEST".  Ambiguous
+// abbreviations resolve to their most common reading, e.g. "IST"
+// is India Standard Time and "CST" is US Central Standard Time.
+func ZoneOffset(abbr string) (int, bool) {
+	offset, ok := zoneOffsets[abbr]
+	return offset, ok
+}
+
+func (p *StandardParser) location() *time.Location {
+	if p.Location != nil {
+		return p.Location
+	}
+	return time.UTC
+}
+
+// zoneOffset resolves a time zone abbreviation which isn't
+// used by the parser's Location.
+func (p *StandardParser) zoneOffset(abbr string) (int, bool) {
+	if offset, ok := p.Zones[abbr]; ok {
+		return offset, true
+	}
+	return ZoneOffset(abbr)
+}
+
+// parseLayout attempts to parse d using the layout.
+//
+// Dates without a zone are read in the parser's Location, and
+// abbreviations are first looked up in that Location so that
+// e.g. "CST" means China Standard Time to a parser set to
+// Asia/Shanghai.  Other abbreviations, which time.Parse would
+// give a zero offset, are resolved with zoneOffset.
+func (p *StandardParser) parseLayout(l *layout, d string) (time.Time, bool) {
+	loc := p.location()
+	t, err := time.ParseInLocation(l.format, d, loc)
+	if err != nil {
+		return t, false
+	}
+	if l.numericZone || t.Location() == loc {
+		return t, true
+	}
+
+	name, offset := t.Zone()
+	if name == "" || offset != 0 {
+		return t, true
+	}
+	if offset, ok := p.zoneOffset(name); ok {
+		t = time.Date(t.Year(), t.Month(), t.Day(),
+			t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+			time.FixedZone(name, offset))
+	}
+	return t, true
+}
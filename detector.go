@@ -28,10 +28,25 @@ const (
 
 // DetectFeedType attempts to determine the type of feed
 // by looking for specific xml elements unique to the
-// various feed types.
+// various feed types.  Formats added with RegisterFormat
+// are tried in the order of their priority.
 func DetectFeedType(feed io.Reader) FeedType {
 	buffer := new(bytes.Buffer)
 	buffer.ReadFrom(feed)
+	data := buffer.Bytes()
+
+	if feedType := detectRegisteredFormat(data, true); feedType != FeedTypeUnknown {
+		return feedType
+	}
+	if feedType := detectBuiltinFeedType(bytes.NewBuffer(data)); feedType != FeedTypeUnknown {
+		return feedType
+	}
+	return detectRegisteredFormat(data, false)
+}
+
+// detectBuiltinFeedType detects the RSS, Atom
+// and JSON documents in buffer.
+func detectBuiltinFeedType(buffer *bytes.Buffer) FeedType {
 
 	var firstChar byte
 	loop: for {
@@ -71,10 +86,32 @@ func DetectFeedType(feed io.Reader) FeedType {
 			return FeedTypeUnknown
 		}
 	} else if firstChar == '{' {
-		// Check if document is valid JSON
-		if jsoniter.Valid(buffer.Bytes()) {
+		// Check if document is a JSON object shaped like a JSON Feed
+		if isJSONFeed(buffer.Bytes()) {
 			return FeedTypeJSON
 		}
 	}
 	return FeedTypeUnknown
 }
+
+// isJSONFeed reports whether data is a JSON object with the
+// version or items of a JSON Feed.  JSON-LD documents, such as
+// ActivityStreams, aren't JSON Feeds even if they have items.
+func isJSONFeed(data []byte) bool {
+	var hasContext, hasVersion, hasItems bool
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, name string) bool {
+		switch name {
+		case "@context":
+			hasContext = true
+		case "version":
+			hasVersion = true
+		case "items":
+			hasItems = true
+		}
+		iter.Skip()
+		return iter.Error == nil
+	})
+	return iter.Error == nil && !hasContext && (hasVersion || hasItems)
+}
@@ -25,6 +25,10 @@ func TestDetectFeedType(t *testing.T) {
 		{"unknown_feed.xml", gofeed.FeedTypeUnknown},
 		{"empty_feed.xml", gofeed.FeedTypeUnknown},
 		{"json10_feed.json", gofeed.FeedTypeJSON},
+		{"hfeed.html", gofeed.FeedTypeHFeed},
+		{"activitystreams_outbox.json", gofeed.FeedTypeActivityStreams},
+		{"plain.json", gofeed.FeedTypeUnknown},
+		{"html_page.html", gofeed.FeedTypeUnknown},
 	}
 
 	for _, test := range feedTypeTests {
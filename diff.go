@@ -0,0 +1,156 @@
+package gofeed
+
+import (
+	"strings"
+	"time"
+)
+
+// Names of the item fields compared by Diff.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldContent     = "content"
+	FieldEnclosures  = "enclosures"
+	FieldUpdated     = "updated"
+)
+
+// IdentityFunc returns the key which identifies an item
+// across snapshots of its feed.
+type IdentityFunc func(item *Item) string
+
+// FeedDiff holds the differences between two snapshots
+// of the same feed.
+type FeedDiff struct {
+	// Added are the items only in the newer snapshot,
+	// in feed order.
+	Added []*Item
+	// Removed are the items only in the older snapshot,
+	// in feed order.
+	Removed []*Item
+	// Changed are the items in both snapshots whose
+	// compared fields differ, in the newer feed's order.
+	Changed []*ItemChange
+}
+
+// ItemChange is an item which changed between snapshots.
+type ItemChange struct {
+	Old    *Item
+	New    *Item
+	Fields []*FieldChange
+}
+
+// FieldChange is a change to a single field of an item.
+// Enclosures are rendered one per line and dates in
+// RFC 3339 format when they could be parsed.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Empty reports whether the snapshots had no differences.
+func (d *FeedDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares two snapshots of the same feed and reports the
+// items which were added, removed or changed.  Items are matched
+// by identity, which defaults to Item.Identity when nil; of items
+// sharing an identity only the first is considered.  A nil feed
+// is treated as a feed without items.
+func Diff(prev, next *Feed, identity IdentityFunc) *FeedDiff {
+	if identity == nil {
+		identity = (*Item).Identity
+	}
+	prevItems, prevByID := indexItems(prev, identity)
+	nextItems, nextByID := indexItems(next, identity)
+
+	diff := &FeedDiff{}
+	for _, k := range nextItems {
+		was, ok := prevByID[k.id]
+		if !ok {
+			diff.Added = append(diff.Added, k.item)
+			continue
+		}
+		if fields := diffItem(was, k.item); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, &ItemChange{Old: was, New: k.item, Fields: fields})
+		}
+	}
+	for _, k := range prevItems {
+		if _, ok := nextByID[k.id]; !ok {
+			diff.Removed = append(diff.Removed, k.item)
+		}
+	}
+	return diff
+}
+
+type keyedItem struct {
+	id   string
+	item *Item
+}
+
+// indexItems returns the items of a feed with their identities,
+// dropping nil items and repeated identities, and a map from
+// identity to item.
+func indexItems(feed *Feed, identity IdentityFunc) ([]keyedItem, map[string]*Item) {
+	byID := map[string]*Item{}
+	if feed == nil {
+		return nil, byID
+	}
+	var items []keyedItem
+	for _, item := range feed.Items {
+		if item == nil {
+			continue
+		}
+		id := identity(item)
+		if _, ok := byID[id]; ok {
+			continue
+		}
+		byID[id] = item
+		items = append(items, keyedItem{id, item})
+	}
+	return items, byID
+}
+
+// diffItem returns the changes to the compared fields of an item.
+func diffItem(prev, next *Item) []*FieldChange {
+	var fields []*FieldChange
+	add := func(field, o, n string) {
+		if o != n {
+			fields = append(fields, &FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	add(FieldTitle, prev.Title, next.Title)
+	add(FieldDescription, prev.Description, next.Description)
+	add(FieldContent, prev.Content, next.Content)
+	add(FieldEnclosures, enclosuresString(prev.Enclosures), enclosuresString(next.Enclosures))
+
+	// Compare parsed dates so that a change of format
+	// or time zone alone isn't reported
+	o, n := dateString(prev.Updated, prev.UpdatedParsed), dateString(next.Updated, next.UpdatedParsed)
+	if prev.UpdatedParsed != nil && next.UpdatedParsed != nil {
+		if !prev.UpdatedParsed.Equal(*next.UpdatedParsed) {
+			add(FieldUpdated, o, n)
+		}
+	} else {
+		add(FieldUpdated, o, n)
+	}
+	return fields
+}
+
+func enclosuresString(enclosures []*Enclosure) string {
+	var lines []string
+	for _, enc := range enclosures {
+		if enc != nil {
+			lines = append(lines, strings.TrimSpace(enc.URL+" "+enc.Type+" "+enc.Length))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dateString(raw string, parsed *time.Time) string {
+	if parsed != nil {
+		return parsed.Format(time.RFC3339)
+	}
+	return raw
+}
@@ -0,0 +1,86 @@
+package gofeed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	updated := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	later := updated.Add(time.Hour)
+
+	prev := &gofeed.Feed{Items: []*gofeed.Item{
+		{GUID: "1", Title: "Unchanged", UpdatedParsed: &updated},
+		{GUID: "2", Title: "Old title", Content: "text"},
+		{GUID: "3", Title: "Removed"},
+		{GUID: "4", Enclosures: []*gofeed.Enclosure{{URL: "http://example.org/4.mp3", Type: "audio/mpeg"}}},
+		{GUID: "5", UpdatedParsed: &updated},
+	}}
+	next := &gofeed.Feed{Items: []*gofeed.Item{
+		{GUID: "6", Title: "Added"},
+		{GUID: "5", UpdatedParsed: &later},
+		{GUID: "4", Enclosures: []*gofeed.Enclosure{{URL: "http://example.org/4.m4a", Type: "audio/mp4"}}},
+		{GUID: "2", Title: "New title", Content: "text"},
+		// Same instant in another zone
+		{GUID: "1", Title: "Unchanged", UpdatedParsed: &[]time.Time{updated.In(time.FixedZone("X", 3600))}[0]},
+		nil,
+	}}
+
+	diff := gofeed.Diff(prev, next, nil)
+	assert.False(t, diff.Empty())
+
+	if assert.Len(t, diff.Added, 1) {
+		assert.Equal(t, "6", diff.Added[0].GUID)
+	}
+	if assert.Len(t, diff.Removed, 1) {
+		assert.Equal(t, "3", diff.Removed[0].GUID)
+	}
+
+	if assert.Len(t, diff.Changed, 3) {
+		assert.Equal(t, "5", diff.Changed[0].New.GUID)
+		assert.Equal(t, []*gofeed.FieldChange{
+			{Field: gofeed.FieldUpdated, Old: "2006-01-02T15:04:05Z", New: "2006-01-02T16:04:05Z"},
+		}, diff.Changed[0].Fields)
+
+		assert.Equal(t, "4", diff.Changed[1].New.GUID)
+		assert.Equal(t, []*gofeed.FieldChange{
+			{Field: gofeed.FieldEnclosures, Old: "http://example.org/4.mp3 audio/mpeg", New: "http://example.org/4.m4a audio/mp4"},
+		}, diff.Changed[1].Fields)
+
+		assert.Equal(t, prev.Items[1], diff.Changed[2].Old)
+		assert.Equal(t, next.Items[3], diff.Changed[2].New)
+		assert.Equal(t, []*gofeed.FieldChange{
+			{Field: gofeed.FieldTitle, Old: "Old title", New: "New title"},
+		}, diff.Changed[2].Fields)
+	}
+
+	assert.True(t, gofeed.Diff(prev, prev, nil).Empty())
+}
+
+func TestDiff_Identity(t *testing.T) {
+	prev := &gofeed.Feed{Items: []*gofeed.Item{{GUID: "a", Title: "Post", Content: "v1"}}}
+	next := &gofeed.Feed{Items: []*gofeed.Item{{GUID: "b", Title: "Post", Content: "v2"}}}
+
+	diff := gofeed.Diff(prev, next, nil)
+	assert.Len(t, diff.Added, 1)
+	assert.Len(t, diff.Removed, 1)
+
+	byTitle := func(item *gofeed.Item) string { return item.Title }
+	diff = gofeed.Diff(prev, next, byTitle)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	if assert.Len(t, diff.Changed, 1) {
+		assert.Equal(t, gofeed.FieldContent, diff.Changed[0].Fields[0].Field)
+	}
+}
+
+func TestDiff_NilFeeds(t *testing.T) {
+	feed := &gofeed.Feed{Items: []*gofeed.Item{{GUID: "1"}}}
+
+	assert.Len(t, gofeed.Diff(nil, feed, nil).Added, 1)
+	assert.Len(t, gofeed.Diff(feed, nil, nil).Removed, 1)
+	assert.True(t, gofeed.Diff(nil, nil, nil).Empty())
+}
@@ -44,3 +44,16 @@ func parseTextArrayExtension(name string, extensions map[string][]Extension) (va
 	}
 	return
 }
+
+// attrOrChild returns the value of an attribute of an extension
+// element, or failing that of its first child of the same name,
+// which is how JSON feeds give what XML feeds put in attributes.
+func attrOrChild(e Extension, name string) string {
+	if value, ok := e.Attrs[name]; ok {
+		return value
+	}
+	if children := e.Children[name]; len(children) > 0 {
+		return children[0].Value
+	}
+	return ""
+}
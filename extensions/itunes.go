@@ -96,7 +96,11 @@ func parseImage(extensions map[string][]Extension) (image string) {
 		return
 	}
 
-	image = matches[0].Attrs["href"]
+	// JSON feeds give the URL itself rather than an href
+	image = attrOrChild(matches[0], "href")
+	if image == "" {
+		image = matches[0].Value
+	}
 	return
 }
 
@@ -133,18 +137,23 @@ func parseCategories(extensions map[string][]Extension) (categories []*ITunesCat
 	categories = []*ITunesCategory{}
 	for _, cat := range matches {
 		c := &ITunesCategory{}
-		if text, ok := cat.Attrs["text"]; ok {
-			c.Text = text
-		}
+		c.Text = categoryText(cat)
 
-		if subs, ok := cat.Children["category"]; ok {
+		if subs, ok := cat.Children["category"]; ok && len(subs) > 0 {
 			s := &ITunesCategory{}
-			if text, ok := subs[0].Attrs["text"]; ok {
-				s.Text = text
-			}
+			s.Text = categoryText(subs[0])
 			c.Subcategory = s
 		}
 		categories = append(categories, c)
 	}
 	return
 }
+
+// categoryText returns the text attribute of a category,
+// or for JSON feeds its text member or the category itself.
+func categoryText(cat Extension) string {
+	if text := attrOrChild(cat, "text"); text != "" {
+		return text
+	}
+	return cat.Value
+}
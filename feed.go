@@ -2,6 +2,7 @@ package gofeed
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	ext "github.com/mmcdole/gofeed/extensions"
@@ -11,13 +12,16 @@ import (
 // and rss.Feed gets translated to. It represents
 // a web feed.
 // Sorting with sort.Sort will order the Items by
-// oldest to newest publish time.
+// oldest to newest publish time; SortItems offers
+// more control.
 type Feed struct {
 	Title           string                   `json:"title,omitempty"`
 	Description     string                   `json:"description,omitempty"`
 	Link            string                   `json:"link,omitempty"`
 	FeedLink        string                   `json:"feedLink,omitempty"`
 	Links           []string                 `json:"links,omitempty"`
+	NextLink        string                   `json:"nextLink,omitempty"`
+	Hubs            []*Hub                   `json:"hubs,omitempty"`
 	Updated         string                   `json:"updated,omitempty"`
 	UpdatedParsed   *time.Time               `json:"updatedParsed,omitempty"`
 	Published       string                   `json:"published,omitempty"`
@@ -26,9 +30,11 @@ type Feed struct {
 	Authors         []*Person                `json:"authors,omitempty"`
 	Language        string                   `json:"language,omitempty"`
 	Image           *Image                   `json:"image,omitempty"`
+	Favicon         *Image                   `json:"favicon,omitempty"`
 	Copyright       string                   `json:"copyright,omitempty"`
 	Generator       string                   `json:"generator,omitempty"`
 	Categories      []string                 `json:"categories,omitempty"`
+	Expired         bool                     `json:"expired,omitempty"`
 	DublinCoreExt   *ext.DublinCoreExtension `json:"dcExt,omitempty"`
 	ITunesExt       *ext.ITunesFeedExtension `json:"itunesExt,omitempty"`
 	Extensions      ext.Extensions           `json:"extensions,omitempty"`
@@ -36,6 +42,8 @@ type Feed struct {
 	Items           []*Item                  `json:"items"`
 	FeedType        string                   `json:"feedType"`
 	FeedVersion     string                   `json:"feedVersion"`
+	Repairs         []*Repair                `json:"repairs,omitempty"`
+	Recovered       bool                     `json:"recovered,omitempty"`
 }
 
 func (f Feed) String() string {
@@ -50,29 +58,94 @@ type Item struct {
 	Title           string                   `json:"title,omitempty"`
 	Description     string                   `json:"description,omitempty"`
 	Content         string                   `json:"content,omitempty"`
+	ContentParts    []*ContentPart           `json:"contentParts,omitempty"`
 	Link            string                   `json:"link,omitempty"`
 	Links           []string                 `json:"links,omitempty"`
 	Updated         string                   `json:"updated,omitempty"`
 	UpdatedParsed   *time.Time               `json:"updatedParsed,omitempty"`
 	Published       string                   `json:"published,omitempty"`
 	PublishedParsed *time.Time               `json:"publishedParsed,omitempty"`
+	DateParsed      *time.Time               `json:"dateParsed,omitempty"`
+	DateSource      string                   `json:"dateSource,omitempty"`
 	Author          *Person                  `json:"author,omitempty"` // Deprecated: Use item.Authors instead
 	Authors         []*Person                `json:"authors,omitempty"`
 	GUID            string                   `json:"guid,omitempty"`
 	Image           *Image                   `json:"image,omitempty"`
 	Categories      []string                 `json:"categories,omitempty"`
 	Enclosures      []*Enclosure             `json:"enclosures,omitempty"`
+	Source          *Source                  `json:"source,omitempty"`
+	Comments        *Comments                `json:"comments,omitempty"`
+	InReplyTo       []*InReplyTo             `json:"inReplyTo,omitempty"`
 	DublinCoreExt   *ext.DublinCoreExtension `json:"dcExt,omitempty"`
 	ITunesExt       *ext.ITunesItemExtension `json:"itunesExt,omitempty"`
 	Extensions      ext.Extensions           `json:"extensions,omitempty"`
 	Custom          map[string]string        `json:"custom,omitempty"`
 }
 
+// Sources of Item.DateParsed, the effective date of an item.
+// It is the first of the item's published date, its updated
+// date, its dc:date, the date of its feed and the time it was
+// first seen which is known.
+const (
+	DateSourcePublished  = "published"
+	DateSourceUpdated    = "updated"
+	DateSourceDublinCore = "dc:date"
+	DateSourceFeed       = "feed"
+	DateSourceFirstSeen  = "first-seen"
+)
+
+// Types of content parts.  Other content parts
+// have the MIME type of their content.
+const (
+	ContentTypeText  = "text"
+	ContentTypeHTML  = "html"
+	ContentTypeXHTML = "xhtml"
+)
+
+// ContentPart is one representation of the content of an
+// item, such as the HTML or the plain text of JSON Feed items
+// or Atom content of any type.  Item.Content holds the part
+// the translator thinks best.
+type ContentPart struct {
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Src is the URL of content which isn't in
+	// the feed, in which case Value is empty.
+	Src string `json:"src,omitempty"`
+}
+
+// isHTML reports whether the part is HTML or XHTML, or has
+// a MIME type of HTML or of XML markup such as SVG.
+func (c *ContentPart) isHTML() bool {
+	if c.Type == ContentTypeHTML || c.Type == ContentTypeXHTML {
+		return true
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(c.Type, ";", 2)[0]))
+	return strings.HasSuffix(mediaType, "/html") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// ContentOfType returns the first content part of the item
+// with the first of the types it has, or nil.
+func (i *Item) ContentOfType(types ...string) *ContentPart {
+	for _, t := range types {
+		for _, part := range i.ContentParts {
+			if part != nil && part.Type == t {
+				return part
+			}
+		}
+	}
+	return nil
+}
+
 // Person is an individual specified in a feed
 // (e.g. an author)
 type Person struct {
 	Name  string `json:"name,omitempty"`
 	Email string `json:"email,omitempty"`
+	// URI is the person's web site and Avatar the
+	// URL of an image of them.
+	URI    string `json:"uri,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
 }
 
 // Image is an image that is the artwork for a given
@@ -80,13 +153,95 @@ type Person struct {
 type Image struct {
 	URL   string `json:"url,omitempty"`
 	Title string `json:"title,omitempty"`
+	// Link is the page the image links to.
+	Link        string `json:"link,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Width and Height are the size of the image in
+	// pixels, or zero if the feed doesn't give them.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
 }
 
 // Enclosure is a file associated with a given Item.
 type Enclosure struct {
-	URL    string `json:"url,omitempty"`
+	URL string `json:"url,omitempty"`
+	// Length is the size of the file as given by the
+	// feed, and Size that size in bytes if it's valid.
 	Length string `json:"length,omitempty"`
+	Size   int64  `json:"size,omitempty"`
 	Type   string `json:"type,omitempty"`
+	Title  string `json:"title,omitempty"`
+	// Duration is how long the media plays for,
+	// or zero if the feed doesn't say.  It is
+	// written to JSON as a number of seconds.
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// enclosureJSON is the JSON form of an Enclosure,
+// which has its duration in seconds.
+type enclosureJSON struct {
+	*enclosure
+	Duration float64 `json:"duration,omitempty"`
+}
+
+type enclosure Enclosure
+
+// MarshalJSON writes the enclosure with
+// its duration in seconds.
+func (e Enclosure) MarshalJSON() ([]byte, error) {
+	return json.Marshal(enclosureJSON{
+		enclosure: (*enclosure)(&e),
+		Duration:  e.Duration.Seconds(),
+	})
+}
+
+// UnmarshalJSON reads an enclosure written
+// by MarshalJSON.
+func (e *Enclosure) UnmarshalJSON(data []byte) error {
+	v := enclosureJSON{enclosure: (*enclosure)(e)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.Duration = time.Duration(v.Duration * float64(time.Second))
+	return nil
+}
+
+// Hub is an endpoint which notifies subscribers
+// of updates to a feed, such as a WebSub hub.
+type Hub struct {
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Source is the feed an item was originally published in,
+// such as the feed an aggregated item was taken from.
+type Source struct {
+	Title   string `json:"title,omitempty"`
+	Link    string `json:"link,omitempty"`
+	FeedURL string `json:"feedUrl,omitempty"`
+}
+
+// Comments is where an item is discussed.
+type Comments struct {
+	// Link is the page with the item's comments
+	// and FeedURL a feed of them.
+	Link    string `json:"link,omitempty"`
+	FeedURL string `json:"feedUrl,omitempty"`
+	// Count is the number of comments, or nil
+	// if the feed doesn't give it.
+	Count *int `json:"count,omitempty"`
+}
+
+// InReplyTo is a resource an item is a reply to, as given
+// by the Atom threading extension's thr:in-reply-to.
+type InReplyTo struct {
+	// Ref is the ID of the resource, and Href
+	// where it can be found.
+	Ref  string `json:"ref,omitempty"`
+	Href string `json:"href,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Source is the feed the resource is in.
+	Source string `json:"source,omitempty"`
 }
 
 // Len returns the length of Items.
@@ -96,10 +251,9 @@ func (f Feed) Len() int {
 
 // Less compares PublishedParsed of Items[i], Items[k]
 // and returns true if Items[i] is less than Items[k].
+// Items without a PublishedParsed sort after all others.
 func (f Feed) Less(i, k int) bool {
-	return f.Items[i].PublishedParsed.Before(
-		*f.Items[k].PublishedParsed,
-	)
+	return lessDate(f.Items[i].PublishedParsed, f.Items[k].PublishedParsed, Ascending)
 }
 
 // Swap swaps Items[i] and Items[k].
@@ -1,6 +1,7 @@
 package gofeed_test
 
 import (
+	"encoding/json"
 	"sort"
 	"testing"
 	"time"
@@ -48,3 +49,90 @@ func TestFeedSort(t *testing.T) {
 		}
 	}
 }
+
+func TestFeedSort_MissingDates(t *testing.T) {
+	undated := &gofeed.Item{Title: "undated"}
+	dated := &gofeed.Item{
+		Title:           "dated",
+		PublishedParsed: &[]time.Time{time.Unix(0, 0)}[0],
+	}
+
+	feed := gofeed.Feed{Items: []*gofeed.Item{undated, dated}}
+	sort.Sort(feed)
+
+	if feed.Items[0] != dated || feed.Items[1] != undated {
+		t.Errorf("Items without PublishedParsed should sort last")
+	}
+}
+
+func TestFeed_SortItems(t *testing.T) {
+	at := func(sec int64) *time.Time {
+		return &[]time.Time{time.Unix(sec, 0)}[0]
+	}
+	a := &gofeed.Item{Title: "a", PublishedParsed: at(1), UpdatedParsed: at(5), DateParsed: at(1)}
+	b := &gofeed.Item{Title: "b", PublishedParsed: at(2), DateParsed: at(2)}
+	c := &gofeed.Item{Title: "c", UpdatedParsed: at(3), DateParsed: at(3)}
+	d := &gofeed.Item{Title: "d"}
+
+	tests := []struct {
+		key   gofeed.SortKey
+		order gofeed.SortOrder
+		want  string
+	}{
+		{gofeed.SortByPublished, gofeed.Ascending, "abdc"},
+		{gofeed.SortByPublished, gofeed.Descending, "badc"},
+		{gofeed.SortByUpdated, gofeed.Ascending, "cadb"},
+		{gofeed.SortByUpdated, gofeed.Descending, "acdb"},
+		{gofeed.SortByDate, gofeed.Ascending, "abcd"},
+		{gofeed.SortByDate, gofeed.Descending, "cbad"},
+	}
+
+	// Undated items keep their relative order at the end
+	for _, test := range tests {
+		feed := gofeed.Feed{Items: []*gofeed.Item{d, c, b, a}}
+		feed.SortItems(test.key, test.order)
+
+		got := ""
+		for _, item := range feed.Items {
+			got += item.Title
+		}
+		if got != test.want {
+			t.Errorf("SortItems(%d, %d) = %s; want %s", test.key, test.order, got, test.want)
+		}
+	}
+}
+
+func TestItem_ContentOfType(t *testing.T) {
+	html := &gofeed.ContentPart{Type: gofeed.ContentTypeHTML, Value: "<p>Hi</p>"}
+	text := &gofeed.ContentPart{Type: gofeed.ContentTypeText, Value: "Hi"}
+	item := &gofeed.Item{ContentParts: []*gofeed.ContentPart{html, text}}
+
+	if part := item.ContentOfType(gofeed.ContentTypeText, gofeed.ContentTypeHTML); part != text {
+		t.Errorf("expected the text part, got %v", part)
+	}
+	if part := item.ContentOfType(gofeed.ContentTypeXHTML, gofeed.ContentTypeHTML); part != html {
+		t.Errorf("expected the html part, got %v", part)
+	}
+	if part := item.ContentOfType(gofeed.ContentTypeXHTML); part != nil {
+		t.Errorf("expected no part, got %v", part)
+	}
+}
+
+func TestEnclosure_JSON(t *testing.T) {
+	enc := gofeed.Enclosure{URL: "http://example.org/a.mp3", Duration: 90*time.Minute + 500*time.Millisecond}
+	data, err := json.Marshal(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"url":"http://example.org/a.mp3","duration":5400.5}`; string(data) != want {
+		t.Errorf("Marshal = %s; want %s", data, want)
+	}
+
+	var decoded gofeed.Enclosure
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != enc {
+		t.Errorf("Unmarshal = %+v; want %+v", decoded, enc)
+	}
+}
@@ -0,0 +1,153 @@
+package gofeed
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Format is a feed format which can be added to those the
+// universal parser understands with RegisterFormat.
+type Format struct {
+	// Name identifies the format, such as "hfeed".  It is
+	// returned by the String method of its FeedType.
+	Name string
+
+	// Priority orders the detection of formats.  Formats with
+	// a higher priority are tried first.  The built-in RSS,
+	// Atom and JSON formats have a priority of 0: formats with
+	// a priority above 0 are tried before them and the others
+	// only once none of the built-in formats matched.  Formats
+	// of the same priority are tried in the order they were
+	// registered.
+	Priority int
+
+	// Detect reports whether a document is of this format.
+	Detect func(data []byte) bool
+
+	// Parse parses a document into the native feed type
+	// of the format.
+	Parse func(data []byte, ctx context.Context) (interface{}, error)
+
+	// Translator returns the translator of the native feeds of
+	// the format for a universal parser, which it may take its
+	// DateParser, Sanitizer and other settings from.
+	Translator func(fp *Parser) Translator
+}
+
+type registeredFormat struct {
+	Format
+	feedType FeedType
+}
+
+var formats struct {
+	mu       sync.RWMutex
+	list     []*registeredFormat
+	lastType FeedType
+}
+
+// RegisterFormat adds a feed format to those detected by
+// DetectFeedType and parsed by the universal parser, and
+// returns the FeedType assigned to it.  It panics if the
+// format is incomplete or its name is already taken.
+func RegisterFormat(format Format) FeedType {
+	if format.Name == "" || format.Detect == nil || format.Parse == nil || format.Translator == nil {
+		panic("gofeed: RegisterFormat needs a Name, Detect, Parse and Translator")
+	}
+
+	formats.mu.Lock()
+	defer formats.mu.Unlock()
+
+	if isBuiltinFormatName(format.Name) {
+		panic(fmt.Sprintf("gofeed: format %q is built in", format.Name))
+	}
+	for _, r := range formats.list {
+		if r.Name == format.Name {
+			panic(fmt.Sprintf("gofeed: format %q is already registered", format.Name))
+		}
+	}
+
+	if formats.lastType == FeedTypeUnknown {
+		formats.lastType = FeedTypeJSON
+	}
+	formats.lastType++
+	formats.list = append(formats.list, &registeredFormat{Format: format, feedType: formats.lastType})
+	sort.SliceStable(formats.list, func(i, j int) bool {
+		return formats.list[i].Priority > formats.list[j].Priority
+	})
+	return formats.lastType
+}
+
+// String returns the name of the feed type.
+func (t FeedType) String() string {
+	switch t {
+	case FeedTypeUnknown:
+		return "unknown"
+	case FeedTypeAtom:
+		return "atom"
+	case FeedTypeRSS:
+		return "rss"
+	case FeedTypeJSON:
+		return "json"
+	}
+	if format := lookupFormat(t); format != nil {
+		return format.Name
+	}
+	return fmt.Sprintf("FeedType(%d)", int(t))
+}
+
+func isBuiltinFormatName(name string) bool {
+	for t := FeedTypeUnknown; t <= FeedTypeJSON; t++ {
+		if t.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// detectRegisteredFormat returns the type of the first
+// registered format which detects the document, among
+// those tried before the built-in formats or those tried
+// after them.
+func detectRegisteredFormat(data []byte, beforeBuiltin bool) FeedType {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	for _, format := range formats.list {
+		if (format.Priority > 0) != beforeBuiltin {
+			continue
+		}
+		if format.Detect(data) {
+			return format.feedType
+		}
+	}
+	return FeedTypeUnknown
+}
+
+// lookupFormat returns the registered format
+// of a feed type, or nil if there is none.
+func lookupFormat(t FeedType) *registeredFormat {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	for _, format := range formats.list {
+		if format.feedType == t {
+			return format
+		}
+	}
+	return nil
+}
+
+// parseFormat parses and translates a document
+// of a registered format.
+func (f *Parser) parseFormat(format *registeredFormat, data []byte, ctx context.Context) (*Feed, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	native, err := format.Parse(data, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f.translate(format.Translator(f), native, ctx)
+}
@@ -0,0 +1,124 @@
+package gofeed_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+// linesTranslator translates the lines of a text
+// document into the titles of feed items.
+type linesTranslator struct {
+	prefix string
+}
+
+func (t linesTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	lines, ok := feed.([]string)
+	if !ok {
+		return nil, fmt.Errorf("Feed did not match expected type of []string")
+	}
+	result := &gofeed.Feed{Title: lines[0], FeedType: "lines"}
+	for _, line := range lines[1:] {
+		result.Items = append(result.Items, &gofeed.Item{Title: t.prefix + line})
+	}
+	return result, nil
+}
+
+func parseLines(data []byte, ctx context.Context) (interface{}, error) {
+	return strings.Split(strings.TrimSpace(string(data)), "\n"), nil
+}
+
+var (
+	// Tried before the built-in formats.
+	feedTypeLines = gofeed.RegisterFormat(gofeed.Format{
+		Name:     "lines",
+		Priority: 1,
+		Detect: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("#lines"))
+		},
+		Parse: parseLines,
+		Translator: func(fp *gofeed.Parser) gofeed.Translator {
+			return linesTranslator{prefix: fp.UserAgent + ": "}
+		},
+	})
+
+	// Tried after the built-in formats, so it never
+	// sees the RSS, Atom and JSON documents it detects.
+	feedTypeLateLines = gofeed.RegisterFormat(gofeed.Format{
+		Name: "late-lines",
+		Detect: func(data []byte) bool {
+			return bytes.Contains(data, []byte("late-lines"))
+		},
+		Parse: parseLines,
+		Translator: func(fp *gofeed.Parser) gofeed.Translator {
+			return linesTranslator{}
+		},
+	})
+)
+
+func TestRegisterFormat_Detect(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected gofeed.FeedType
+	}{
+		{"#lines\none", feedTypeLines},
+		{"#late-lines\none", feedTypeLateLines},
+		{"plain text", gofeed.FeedTypeUnknown},
+		{`<rss version="2.0"><channel></channel></rss>`, gofeed.FeedTypeRSS},
+		{`{"version": "1.1", "title": "late-lines"}`, gofeed.FeedTypeJSON},
+		{"", gofeed.FeedTypeUnknown},
+	}
+
+	for _, test := range tests {
+		actual := gofeed.DetectFeedType(strings.NewReader(test.data))
+		assert.Equal(t, test.expected, actual, "%q", test.data)
+	}
+
+	assert.Equal(t, "lines", feedTypeLines.String())
+	assert.Equal(t, "late-lines", feedTypeLateLines.String())
+	assert.Equal(t, "rss", gofeed.FeedTypeRSS.String())
+}
+
+func TestRegisterFormat_Parse(t *testing.T) {
+	fp := gofeed.NewParser()
+	fp.ItemHooks = []gofeed.ItemHook{
+		func(native interface{}, item *gofeed.Item) error {
+			assert.Nil(t, native)
+			return nil
+		},
+	}
+
+	feed, err := fp.ParseString("#lines\none\ntwo")
+	assert.Nil(t, err)
+	assert.Equal(t, "#lines", feed.Title)
+	assert.Equal(t, "lines", feed.FeedType)
+	if assert.Len(t, feed.Items, 2) {
+		assert.Equal(t, "Gofeed/1.0: one", feed.Items[0].Title)
+		assert.Equal(t, "Gofeed/1.0: two", feed.Items[1].Title)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = fp.ParseWithContext(strings.NewReader("#lines\none"), ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestRegisterFormat_Invalid(t *testing.T) {
+	detect := func(data []byte) bool { return false }
+	translator := func(fp *gofeed.Parser) gofeed.Translator { return linesTranslator{} }
+
+	assert.Panics(t, func() {
+		gofeed.RegisterFormat(gofeed.Format{Name: "incomplete", Detect: detect})
+	})
+	assert.Panics(t, func() {
+		gofeed.RegisterFormat(gofeed.Format{Name: "rss", Detect: detect, Parse: parseLines, Translator: translator})
+	})
+	assert.Panics(t, func() {
+		gofeed.RegisterFormat(gofeed.Format{Name: "lines", Detect: detect, Parse: parseLines, Translator: translator})
+	})
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"fmt"
+	"time"
+)
+
+// Thresholds decide when feeds are degraded or dead.
+type Thresholds struct {
+	// DegradedFailures is the number of failures in a
+	// row after which a feed is degraded.  Zero disables
+	// the check.
+	DegradedFailures int
+	// DeadFailures is the number of failures in a row,
+	// spanning at least DeadAfter, after which a feed
+	// is dead.  Zero disables the check.
+	DeadFailures int
+	DeadAfter    time.Duration
+	// StaleAfter is how long a feed may go without new
+	// items before it's degraded.  Zero disables the check.
+	StaleAfter time.Duration
+	// DegradedWarnings is the number of parse warnings in
+	// the last fetch at which a feed is degraded.  Zero
+	// disables the check.
+	DegradedWarnings int
+}
+
+// DefaultThresholds returns thresholds under which a feed is
+// degraded after 3 failures, 90 days without new items or a
+// parse warning, and dead after failing for 30 days and at
+// least 10 fetches.
+func DefaultThresholds() *Thresholds {
+	return &Thresholds{
+		DegradedFailures: 3,
+		DeadFailures:     10,
+		DeadAfter:        30 * 24 * time.Hour,
+		StaleAfter:       90 * 24 * time.Hour,
+		DegradedWarnings: 1,
+	}
+}
+
+// Assessment is the classification of a feed
+// and the action recommended for it.
+type Assessment struct {
+	Status Status
+	Action Action
+	// Reasons explain why the feed isn't healthy
+	// or why the action is recommended.
+	Reasons []string
+	// NewURL is the URL to update the
+	// subscription to for ActionUpdateURL.
+	NewURL string
+}
+
+// Assess classifies a feed at time now.  A nil Thresholds uses
+// DefaultThresholds.  Dead feeds should be unsubscribed from.
+// Feeds which have moved should be updated to their new URL,
+// unless they're dead.  Degraded feeds are worth investigating.
+func (h *Health) Assess(now time.Time, t *Thresholds) Assessment {
+	if t == nil {
+		t = DefaultThresholds()
+	}
+	a := Assessment{Status: StatusHealthy, Action: ActionNone}
+	degrade := func(reason string) {
+		a.Status = StatusDegraded
+		a.Action = ActionInvestigate
+		a.Reasons = append(a.Reasons, reason)
+	}
+
+	if h.Gone {
+		return Assessment{Status: StatusDead, Action: ActionUnsubscribe, Reasons: []string{"feed is gone (410)"}}
+	}
+	if t.DeadFailures > 0 && h.ConsecutiveFailures >= t.DeadFailures && now.Sub(h.FirstFailure) >= t.DeadAfter {
+		return Assessment{
+			Status:  StatusDead,
+			Action:  ActionUnsubscribe,
+			Reasons: []string{fmt.Sprintf("failing since %s", h.FirstFailure.Format(time.RFC3339))},
+		}
+	}
+
+	if t.DegradedFailures > 0 && h.ConsecutiveFailures >= t.DegradedFailures {
+		degrade(fmt.Sprintf("%d failures in a row: %s", h.ConsecutiveFailures, h.LastError))
+	}
+	if t.StaleAfter > 0 && !h.LastNewItem.IsZero() && now.Sub(h.LastNewItem) >= t.StaleAfter {
+		degrade(fmt.Sprintf("no new items since %s", h.LastNewItem.Format(time.RFC3339)))
+	}
+	if t.DegradedWarnings > 0 && h.Warnings >= t.DegradedWarnings {
+		degrade(fmt.Sprintf("%d parse warnings", h.Warnings))
+	}
+
+	if h.MovedTo != "" {
+		a.Action = ActionUpdateURL
+		a.NewURL = h.MovedTo
+		a.Reasons = append(a.Reasons, "moved by "+h.MoveReason)
+	}
+	return a
+}
@@ -0,0 +1,182 @@
+// Package health tracks how well feeds are doing across fetches
+// and classifies them as healthy, degraded or dead, recommending
+// what to do about feeds which have moved, gone or stopped working.
+package health
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Status is the classification of a feed.
+type Status string
+
+// Feed statuses.
+const (
+	StatusHealthy  Status = "healthy"
+	StatusDegraded Status = "degraded"
+	StatusDead     Status = "dead"
+)
+
+// Action is what should be done about a feed.
+type Action string
+
+// Recommended actions.
+const (
+	ActionNone        Action = "none"
+	ActionUpdateURL   Action = "update-url"
+	ActionInvestigate Action = "investigate"
+	ActionUnsubscribe Action = "unsubscribe"
+)
+
+// Reasons a feed has moved.
+const (
+	MovedNewFeedURL        = "itunes:new-feed-url"
+	MovedPermanentRedirect = "permanent redirect"
+)
+
+// Result is the outcome of a fetch of a feed, such as the
+// values returned by gofeed.Parser.ParseURLWithContext.
+type Result struct {
+	// URL is the URL the feed was fetched from.
+	URL string
+	// Feed and Err are the parsed feed and the error
+	// of the fetch.
+	Feed *gofeed.Feed
+	Err  error
+	// StatusCode is the HTTP status of the response, if
+	// known.  It's taken from Err if that's a gofeed.HTTPError.
+	StatusCode int
+	// FinalURL is the URL after any redirects, and
+	// PermanentRedirect reports whether all of them
+	// were permanent (301 or 308).
+	FinalURL          string
+	PermanentRedirect bool
+	// Time is when the fetch was made.  The zero
+	// time stands for the time it's recorded.
+	Time time.Time
+}
+
+// Health is the health record of a feed.
+type Health struct {
+	// ConsecutiveFailures is the number of fetches in a
+	// row which failed, the first of them at FirstFailure.
+	ConsecutiveFailures int       `json:"consecutiveFailures,omitempty"`
+	FirstFailure        time.Time `json:"firstFailure,omitempty"`
+	LastStatus          int       `json:"lastStatus,omitempty"`
+	LastError           string    `json:"lastError,omitempty"`
+	LastAttempt         time.Time `json:"lastAttempt,omitempty"`
+	LastSuccess         time.Time `json:"lastSuccess,omitempty"`
+	// LastNewItem is the date of the newest item seen.
+	// Items dated by their feed or when they were first
+	// seen don't count.
+	LastNewItem time.Time `json:"lastNewItem,omitempty"`
+	// Gone is set when the feed responded 410 Gone.
+	Gone bool `json:"gone,omitempty"`
+	// MovedTo is the URL the feed has moved to,
+	// for the reason given by MoveReason.
+	MovedTo    string `json:"movedTo,omitempty"`
+	MoveReason string `json:"moveReason,omitempty"`
+	// Warnings is the number of problems which had to be
+	// worked around to parse the feed the last time it was
+	// fetched (see gofeed.Feed.Repairs and Recovered), and
+	// TotalWarnings their number over all fetches.  Cosmetic
+	// repairs, such as an escaped ampersand, don't count.
+	Warnings      int `json:"warnings,omitempty"`
+	TotalWarnings int `json:"totalWarnings,omitempty"`
+}
+
+// cosmeticRepairs are the kinds of repairs which don't lose
+// any of the feed and are too common to be worth a warning.
+var cosmeticRepairs = map[string]bool{
+	gofeed.RepairIllegalChar: true,
+	gofeed.RepairAmpersand:   true,
+	gofeed.RepairHTMLEntity:  true,
+}
+
+// Record updates the health of a feed with the result of a fetch.
+// A result without a feed or an error, as for a 304 Not Modified
+// response, counts as a success which leaves what is known about
+// the feed's content unchanged.
+func (h *Health) Record(r Result) {
+	at := r.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	h.LastAttempt = at
+
+	var httpErr gofeed.HTTPError
+	if errors.As(r.Err, &httpErr) && r.StatusCode == 0 {
+		r.StatusCode = httpErr.StatusCode
+	}
+	h.LastStatus = r.StatusCode
+
+	if r.Err != nil {
+		if h.ConsecutiveFailures == 0 {
+			h.FirstFailure = at
+		}
+		h.ConsecutiveFailures++
+		h.LastError = r.Err.Error()
+		h.Gone = r.StatusCode == http.StatusGone
+		return
+	}
+
+	h.ConsecutiveFailures = 0
+	h.FirstFailure = time.Time{}
+	h.LastError = ""
+	h.LastSuccess = at
+	h.Gone = false
+
+	// An unmodified feed still has the new-feed-url it had
+	if r.Feed != nil {
+		h.MovedTo, h.MoveReason = "", ""
+	}
+	if r.PermanentRedirect && r.FinalURL != "" && r.FinalURL != r.URL {
+		h.MovedTo, h.MoveReason = r.FinalURL, MovedPermanentRedirect
+	}
+	if r.Feed == nil {
+		return
+	}
+
+	if itunes := r.Feed.ITunesExt; itunes != nil {
+		if u := strings.TrimSpace(itunes.NewFeedURL); u != "" && u != r.URL && u != r.FinalURL {
+			h.MovedTo, h.MoveReason = u, MovedNewFeedURL
+		}
+	}
+
+	h.Warnings = 0
+	for _, repair := range r.Feed.Repairs {
+		if repair != nil && !cosmeticRepairs[repair.Kind] {
+			h.Warnings++
+		}
+	}
+	if r.Feed.Recovered {
+		h.Warnings++
+	}
+	h.TotalWarnings += h.Warnings
+
+	if newest := newestItem(r.Feed); newest.After(h.LastNewItem) {
+		h.LastNewItem = newest
+	}
+}
+
+// newestItem returns the date of the newest item of a feed.
+func newestItem(feed *gofeed.Feed) time.Time {
+	var newest time.Time
+	for _, item := range feed.Items {
+		if item == nil || item.DateParsed == nil {
+			continue
+		}
+		if item.DateSource == gofeed.DateSourceFeed || item.DateSource == gofeed.DateSourceFirstSeen {
+			continue
+		}
+		if item.DateParsed.After(newest) {
+			newest = *item.DateParsed
+		}
+	}
+	return newest
+}
@@ -0,0 +1,145 @@
+package health_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/health"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+func day(n int) time.Time {
+	return start.Add(time.Duration(n) * 24 * time.Hour)
+}
+
+func TestHealth_Record_Failures(t *testing.T) {
+	var h health.Health
+
+	h.Record(health.Result{Err: errors.New("timeout"), Time: day(0)})
+	h.Record(health.Result{Err: gofeed.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}, Time: day(1)})
+	assert.Equal(t, 2, h.ConsecutiveFailures)
+	assert.Equal(t, day(0), h.FirstFailure)
+	assert.Equal(t, 503, h.LastStatus)
+	assert.Equal(t, "http error: 503 Service Unavailable", h.LastError)
+	assert.False(t, h.Gone)
+
+	h.Record(health.Result{Feed: &gofeed.Feed{}, StatusCode: 200, Time: day(2)})
+	assert.Equal(t, 0, h.ConsecutiveFailures)
+	assert.True(t, h.FirstFailure.IsZero())
+	assert.Equal(t, "", h.LastError)
+	assert.Equal(t, day(2), h.LastSuccess)
+
+	h.Record(health.Result{Err: gofeed.HTTPError{StatusCode: 410, Status: "410 Gone"}})
+	assert.True(t, h.Gone)
+	assert.False(t, h.LastAttempt.IsZero())
+}
+
+func TestHealth_Record_Moves(t *testing.T) {
+	var h health.Health
+	feedURL := "http://example.org/feed"
+
+	h.Record(health.Result{URL: feedURL, Feed: &gofeed.Feed{}, FinalURL: "http://example.org/temp", PermanentRedirect: false})
+	assert.Equal(t, "", h.MovedTo)
+
+	h.Record(health.Result{URL: feedURL, Feed: &gofeed.Feed{}, FinalURL: "https://example.org/feed", PermanentRedirect: true})
+	assert.Equal(t, "https://example.org/feed", h.MovedTo)
+	assert.Equal(t, health.MovedPermanentRedirect, h.MoveReason)
+
+	podcast := &gofeed.Feed{ITunesExt: &ext.ITunesFeedExtension{NewFeedURL: " http://example.com/podcast "}}
+	h.Record(health.Result{URL: feedURL, Feed: podcast})
+	assert.Equal(t, "http://example.com/podcast", h.MovedTo)
+	assert.Equal(t, health.MovedNewFeedURL, h.MoveReason)
+
+	// Unmodified feeds keep their new-feed-url
+	h.Record(health.Result{URL: feedURL, StatusCode: 304})
+	assert.Equal(t, "http://example.com/podcast", h.MovedTo)
+
+	// A new-feed-url pointing at the feed itself is ignored
+	self := &gofeed.Feed{ITunesExt: &ext.ITunesFeedExtension{NewFeedURL: feedURL}}
+	h.Record(health.Result{URL: feedURL, Feed: self})
+	assert.Equal(t, "", h.MovedTo)
+}
+
+func TestHealth_Record_Content(t *testing.T) {
+	var h health.Health
+	published, updated := day(-10), day(-5)
+
+	feed := &gofeed.Feed{
+		Repairs:   []*gofeed.Repair{{}, {}},
+		Recovered: true,
+		Items: []*gofeed.Item{
+			{DateParsed: &published, DateSource: gofeed.DateSourcePublished},
+			{DateParsed: &updated, DateSource: gofeed.DateSourceUpdated},
+			{DateParsed: &[]time.Time{day(0)}[0], DateSource: gofeed.DateSourceFeed},
+			nil,
+		},
+	}
+	h.Record(health.Result{Feed: feed})
+	assert.Equal(t, 3, h.Warnings)
+	assert.Equal(t, updated, h.LastNewItem)
+
+	h.Record(health.Result{Feed: &gofeed.Feed{Items: feed.Items[:1]}})
+	assert.Equal(t, 0, h.Warnings)
+	assert.Equal(t, 3, h.TotalWarnings)
+	assert.Equal(t, updated, h.LastNewItem)
+
+	// Cosmetic repairs aren't warnings
+	h.Record(health.Result{Feed: &gofeed.Feed{Repairs: []*gofeed.Repair{
+		{Kind: gofeed.RepairHTMLEntity},
+		{Kind: gofeed.RepairAmpersand},
+	}}})
+	assert.Equal(t, 0, h.Warnings)
+	assert.Equal(t, health.StatusHealthy, h.Assess(day(0), nil).Status)
+}
+
+func TestHealth_Assess(t *testing.T) {
+	now := day(100)
+
+	tests := []struct {
+		name   string
+		health health.Health
+		status health.Status
+		action health.Action
+	}{
+		{"healthy", health.Health{LastNewItem: day(99)}, health.StatusHealthy, health.ActionNone},
+		{"failing", health.Health{ConsecutiveFailures: 3, FirstFailure: day(99)}, health.StatusDegraded, health.ActionInvestigate},
+		{"failing long", health.Health{ConsecutiveFailures: 10, FirstFailure: day(60)}, health.StatusDead, health.ActionUnsubscribe},
+		{"failing often", health.Health{ConsecutiveFailures: 50, FirstFailure: day(90)}, health.StatusDegraded, health.ActionInvestigate},
+		{"gone", health.Health{Gone: true, MovedTo: "http://example.org/"}, health.StatusDead, health.ActionUnsubscribe},
+		{"stale", health.Health{LastNewItem: day(5)}, health.StatusDegraded, health.ActionInvestigate},
+		{"warnings", health.Health{Warnings: 1}, health.StatusDegraded, health.ActionInvestigate},
+		{"moved", health.Health{MovedTo: "http://example.org/", Warnings: 1}, health.StatusDegraded, health.ActionUpdateURL},
+	}
+
+	for _, test := range tests {
+		a := test.health.Assess(now, nil)
+		assert.Equal(t, test.status, a.Status, test.name)
+		assert.Equal(t, test.action, a.Action, test.name)
+		if a.Status != health.StatusHealthy {
+			assert.NotEmpty(t, a.Reasons, test.name)
+		}
+	}
+
+	moved := health.Health{MovedTo: "http://example.org/", MoveReason: health.MovedNewFeedURL}
+	a := moved.Assess(now, nil)
+	assert.Equal(t, health.StatusHealthy, a.Status)
+	assert.Equal(t, "http://example.org/", a.NewURL)
+	assert.Equal(t, []string{"moved by itunes:new-feed-url"}, a.Reasons)
+
+	// Checks can be disabled
+	stale := health.Health{LastNewItem: day(0), Warnings: 5}
+	a = stale.Assess(now, &health.Thresholds{DegradedFailures: 3, DeadFailures: 10})
+	assert.Equal(t, health.StatusHealthy, a.Status)
+
+	// Thresholds which are left out are disabled too
+	a = (&health.Health{}).Assess(now, &health.Thresholds{StaleAfter: 24 * time.Hour})
+	assert.Equal(t, health.StatusHealthy, a.Status)
+	failing := health.Health{ConsecutiveFailures: 5, FirstFailure: day(0)}
+	a = failing.Assess(now, &health.Thresholds{DegradedFailures: 3})
+	assert.Equal(t, health.StatusDegraded, a.Status)
+}
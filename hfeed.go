@@ -0,0 +1,40 @@
+package gofeed
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/mmcdole/gofeed/hfeed"
+	"golang.org/x/net/html"
+)
+
+// FeedTypeHFeed represents an HTML page with microformats2
+// h-feed or h-entry markup.  It is tried after the RSS, Atom
+// and JSON formats.
+var FeedTypeHFeed = RegisterFormat(Format{
+	Name:   "hfeed",
+	Detect: detectHFeed,
+	Parse: func(data []byte, ctx context.Context) (interface{}, error) {
+		return (&hfeed.Parser{}).ParseWithContext(bytes.NewReader(data), ctx)
+	},
+	Translator: func(fp *Parser) Translator {
+		return fp.hfeedTrans()
+	},
+})
+
+// detectHFeed reports whether a document is HTML
+// with an h-feed or h-entry element.
+func detectHFeed(data []byte) bool {
+	if !looksLikeXML(data) || !(bytes.Contains(data, []byte("h-feed")) || bytes.Contains(data, []byte("h-entry"))) {
+		return false
+	}
+	doc, err := html.Parse(bytes.NewReader(data))
+	return err == nil && hfeed.HasFeed(doc)
+}
+
+func (f *Parser) hfeedTrans() Translator {
+	if f.HFeedTranslator != nil {
+		return f.HFeedTranslator
+	}
+	return &DefaultHFeedTranslator{TranslatorOptions: f.translatorOptions()}
+}
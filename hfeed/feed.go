@@ -0,0 +1,58 @@
+package hfeed
+
+import (
+	"encoding/json"
+)
+
+// Feed is an h-feed, the microformats2 markup of a feed
+// in an HTML page.  Pages without an h-feed but with h-entry
+// elements give a feed of those entries, named after the page.
+// http://microformats.org/wiki/h-feed
+type Feed struct {
+	Name     string   `json:"name,omitempty"`     // p-name, or the title of the page
+	Summary  string   `json:"summary,omitempty"`  // p-summary
+	URL      string   `json:"url,omitempty"`      // u-url
+	UID      string   `json:"uid,omitempty"`      // u-uid
+	Photo    string   `json:"photo,omitempty"`    // u-photo
+	Authors  []*Card  `json:"authors,omitempty"`  // p-author
+	Language string   `json:"language,omitempty"` // the lang of the h-feed or of the page
+	Entries  []*Entry `json:"entries"`            // the h-entry children of the h-feed
+}
+
+func (f Feed) String() string {
+	json, _ := json.MarshalIndent(f, "", "    ")
+	return string(json)
+}
+
+// Entry is an h-entry of a feed.
+// http://microformats.org/wiki/h-entry
+type Entry struct {
+	Name       string   `json:"name,omitempty"`       // p-name
+	Summary    string   `json:"summary,omitempty"`    // p-summary
+	Content    *Content `json:"content,omitempty"`    // e-content
+	Published  string   `json:"published,omitempty"`  // dt-published
+	Updated    string   `json:"updated,omitempty"`    // dt-updated
+	Authors    []*Card  `json:"authors,omitempty"`    // p-author
+	URL        string   `json:"url,omitempty"`        // u-url
+	UID        string   `json:"uid,omitempty"`        // u-uid
+	Categories []string `json:"categories,omitempty"` // p-category
+	Photos     []string `json:"photos,omitempty"`     // u-photo
+}
+
+// Content is the e-content of an entry, as
+// HTML and as plain text.
+type Content struct {
+	HTML  string `json:"html,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Card is an h-card, the author of a feed or entry.
+// Authors given as plain text only have a Name, or a
+// URL for those marked up with u-author.
+// http://microformats.org/wiki/h-card
+type Card struct {
+	Name  string `json:"name,omitempty"`  // p-name
+	URL   string `json:"url,omitempty"`   // u-url
+	Photo string `json:"photo,omitempty"` // u-photo
+	Email string `json:"email,omitempty"` // u-email, without mailto:
+}
@@ -0,0 +1,585 @@
+package hfeed
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed/internal/shared"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNoFeed is returned for pages with
+// neither an h-feed nor any h-entry.
+var ErrNoFeed = errors.New("Failed to find an h-feed or h-entry in the document")
+
+// Parser is a microformats2 h-feed parser
+// for HTML pages.
+type Parser struct{}
+
+// Parse parses the h-feed of an HTML page into an hfeed.Feed.
+func (hp *Parser) Parse(feed io.Reader) (*Feed, error) {
+	return hp.ParseWithContext(feed, context.Background())
+}
+
+// ParseWithContext is like Parse but stops with the error of
+// the context once it's done.  The context is checked as the
+// document is read.
+func (hp *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Feed, error) {
+	doc, err := html.Parse(shared.NewContextReader(ctx, feed))
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p := &parser{base: documentBase(doc)}
+	return p.parseDocument(doc)
+}
+
+// HasFeed reports whether an HTML document has an
+// h-feed or h-entry element.
+func HasFeed(doc *html.Node) bool {
+	return findRoot(doc, "h-feed") != nil || findRoot(doc, "h-entry") != nil
+}
+
+type parser struct {
+	// base is the URL of the <base> element of the page,
+	// which relative u-* properties are resolved against.
+	base *url.URL
+}
+
+// microformat is an element with h-* classes along
+// with its properties and child microformats.
+type microformat struct {
+	node     *html.Node
+	types    []string
+	props    map[string][]*property
+	children []*microformat
+
+	// Whether there are explicit p-* or e-*
+	// properties, u-* properties or nested
+	// microformats, which prevent the implied
+	// name, url and photo.
+	hasText   bool
+	hasURL    bool
+	hasNested bool
+}
+
+// property is the value of a single property.  html is
+// only set for e-* properties and mf for properties
+// which are microformats themselves, like p-author h-card.
+type property struct {
+	value string
+	html  string
+	mf    *microformat
+}
+
+func (p *parser) parseDocument(doc *html.Node) (*Feed, error) {
+	if node := findRoot(doc, "h-feed"); node != nil {
+		feed := parseFeed(p.parseMicroformat(node))
+		if feed.Name == "" {
+			feed.Name = documentTitle(doc)
+		}
+		return feed, nil
+	}
+
+	// Without an h-feed the top level h-entry elements
+	// make up the feed, which is named after the page.
+	page := &microformat{node: doc, props: map[string][]*property{}}
+	p.walk(page, doc)
+	entries := parseEntries(page)
+	if len(entries) == 0 {
+		return nil, ErrNoFeed
+	}
+
+	feed := &Feed{}
+	feed.Name = documentTitle(doc)
+	feed.Language = language(findElement(doc, atom.Html))
+	feed.Entries = entries
+	return feed, nil
+}
+
+func parseFeed(mf *microformat) *Feed {
+	feed := &Feed{}
+	feed.Name = mf.first("name")
+	feed.Summary = mf.first("summary")
+	feed.URL = mf.first("url")
+	feed.UID = mf.first("uid")
+	feed.Photo = mf.first("photo")
+	feed.Authors = cards(mf.props["author"])
+	feed.Language = language(mf.node)
+	feed.Entries = parseEntries(mf)
+	return feed
+}
+
+// parseEntries parses the h-entry children of a microformat.
+func parseEntries(mf *microformat) []*Entry {
+	entries := []*Entry{}
+	for _, child := range mf.children {
+		if child.is("h-entry") {
+			entries = append(entries, parseEntry(child))
+		}
+	}
+	return entries
+}
+
+func parseEntry(mf *microformat) *Entry {
+	entry := &Entry{}
+	entry.Name = mf.first("name")
+	entry.Summary = mf.first("summary")
+	for _, prop := range mf.props["content"] {
+		entry.Content = &Content{HTML: prop.html, Value: prop.value}
+		break
+	}
+	entry.Published = mf.first("published")
+	entry.Updated = mf.first("updated")
+	entry.Authors = cards(mf.props["author"])
+	entry.URL = mf.first("url")
+	entry.UID = mf.first("uid")
+	entry.Categories = mf.all("category")
+	entry.Photos = mf.all("photo")
+	return entry
+}
+
+// cards returns the h-card of each author property, or a
+// card holding the text of authors which aren't h-cards.
+func cards(props []*property) (cards []*Card) {
+	for _, prop := range props {
+		card := &Card{}
+		if prop.mf != nil && prop.mf.is("h-card") {
+			card.Name = prop.mf.first("name")
+			card.URL = prop.mf.first("url")
+			card.Photo = prop.mf.first("photo")
+			card.Email = strings.TrimPrefix(prop.mf.first("email"), "mailto:")
+		} else if prop.mf == nil && prop.html == "" && isURL(prop.value) {
+			card.URL = prop.value
+		} else {
+			card.Name = prop.value
+		}
+		if *card != (Card{}) {
+			cards = append(cards, card)
+		}
+	}
+	return
+}
+
+func (mf *microformat) is(typ string) bool {
+	for _, t := range mf.types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// first returns the value of the first property with a name.
+func (mf *microformat) first(name string) string {
+	for _, prop := range mf.props[name] {
+		if prop.value != "" {
+			return prop.value
+		}
+	}
+	return ""
+}
+
+// all returns the values of all properties with a name.
+func (mf *microformat) all(name string) (values []string) {
+	for _, prop := range mf.props[name] {
+		if prop.value != "" {
+			values = append(values, prop.value)
+		}
+	}
+	return
+}
+
+// parseMicroformat parses an element with h-* classes.
+func (p *parser) parseMicroformat(node *html.Node) *microformat {
+	mf := &microformat{
+		node:  node,
+		types: classesWithPrefix(node, "h-"),
+		props: map[string][]*property{},
+	}
+	p.walk(mf, node)
+
+	if _, ok := mf.props["name"]; !ok && !mf.hasText && !mf.hasNested {
+		mf.addImplied("name", impliedName(node))
+	}
+	if _, ok := mf.props["photo"]; !ok && !mf.hasURL && !mf.hasNested {
+		mf.addImplied("photo", p.resolve(impliedPhoto(node)))
+	}
+	if _, ok := mf.props["url"]; !ok && !mf.hasURL && !mf.hasNested {
+		mf.addImplied("url", p.resolve(impliedURL(node)))
+	}
+	return mf
+}
+
+func (mf *microformat) addImplied(name, value string) {
+	if value != "" {
+		mf.props[name] = []*property{{value: value}}
+	}
+}
+
+// walk adds the properties and child microformats found in
+// the descendants of node to mf.  It doesn't descend into
+// microformats, whose properties are their own.
+func (p *parser) walk(mf *microformat, node *html.Node) {
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+
+		var nested *microformat
+		if len(classesWithPrefix(c, "h-")) > 0 {
+			nested = p.parseMicroformat(c)
+			mf.hasNested = true
+		}
+
+		hasProps := false
+		for _, prefix := range []string{"p-", "u-", "dt-", "e-"} {
+			for _, name := range classesWithPrefix(c, prefix) {
+				prop := p.parseProperty(c, prefix, nested)
+				mf.props[name] = append(mf.props[name], prop)
+				switch prefix {
+				case "p-", "e-":
+					mf.hasText = true
+				case "u-":
+					mf.hasURL = true
+				}
+				hasProps = true
+			}
+		}
+
+		if nested == nil {
+			p.walk(mf, c)
+		} else if !hasProps {
+			mf.children = append(mf.children, nested)
+		}
+	}
+}
+
+// parseProperty parses the value of a property element.
+// Properties which are also microformats take the name or
+// url of the microformat as their value.
+func (p *parser) parseProperty(node *html.Node, prefix string, nested *microformat) *property {
+	prop := &property{mf: nested}
+	switch prefix {
+	case "p-":
+		if nested != nil {
+			prop.value = nested.first("name")
+		}
+		if prop.value == "" {
+			prop.value = textValue(node)
+		}
+	case "u-":
+		if nested != nil {
+			prop.value = nested.first("url")
+		}
+		if prop.value == "" {
+			prop.value = p.resolve(urlValue(node))
+		}
+	case "dt-":
+		prop.value = dateValue(node)
+	case "e-":
+		prop.html, _ = goquery.NewDocumentFromNode(node).Html()
+		prop.html = shared.ResolveHTMLURLs(p.base, strings.TrimSpace(prop.html))
+		prop.value = strings.TrimSpace(textContent(node))
+	}
+	return prop
+}
+
+// textValue returns the value of a p-* property.
+func textValue(node *html.Node) string {
+	switch node.DataAtom {
+	case atom.Img, atom.Area:
+		if alt, ok := attr(node, "alt"); ok {
+			return alt
+		}
+	case atom.Abbr, atom.Link:
+		if title, ok := attr(node, "title"); ok {
+			return title
+		}
+	case atom.Data, atom.Input:
+		if value, ok := attr(node, "value"); ok {
+			return value
+		}
+	}
+	return collapse(textContent(node))
+}
+
+// urlValue returns the value of a u-* property.
+func urlValue(node *html.Node) string {
+	var name string
+	switch node.DataAtom {
+	case atom.A, atom.Area, atom.Link:
+		name = "href"
+	case atom.Img, atom.Audio, atom.Source, atom.Iframe:
+		name = "src"
+	case atom.Video:
+		if _, ok := attr(node, "src"); ok {
+			name = "src"
+		} else {
+			name = "poster"
+		}
+	case atom.Object:
+		name = "data"
+	case atom.Abbr:
+		name = "title"
+	case atom.Data, atom.Input:
+		name = "value"
+	}
+	if value, ok := attr(node, name); ok {
+		return strings.TrimSpace(value)
+	}
+	return collapse(textContent(node))
+}
+
+// dateValue returns the value of a dt-* property.
+func dateValue(node *html.Node) string {
+	var name string
+	switch node.DataAtom {
+	case atom.Time, atom.Ins, atom.Del:
+		name = "datetime"
+	case atom.Abbr:
+		name = "title"
+	case atom.Data, atom.Input:
+		name = "value"
+	}
+	if value, ok := attr(node, name); ok {
+		return strings.TrimSpace(value)
+	}
+	return collapse(textContent(node))
+}
+
+// impliedName returns the name of a microformat
+// without a p-name or other text properties.
+func impliedName(node *html.Node) string {
+	for _, n := range []*html.Node{node, onlyChild(node), onlyChild(onlyChild(node))} {
+		if n == nil || (n != node && len(classesWithPrefix(n, "h-")) > 0) {
+			break
+		}
+		// Children only give their alt or title if it's
+		// not empty, while the element itself always does.
+		switch n.DataAtom {
+		case atom.Img, atom.Area:
+			if alt, ok := attr(n, "alt"); ok && (n == node || alt != "") {
+				return strings.TrimSpace(alt)
+			}
+		case atom.Abbr:
+			if title, ok := attr(n, "title"); ok && (n == node || title != "") {
+				return strings.TrimSpace(title)
+			}
+		}
+	}
+	return collapse(textContent(node))
+}
+
+// impliedPhoto returns the photo of a microformat
+// without a u-photo or other url properties.
+func impliedPhoto(node *html.Node) string {
+	for _, n := range []*html.Node{node, onlyChild(node), onlyChild(onlyChild(node))} {
+		if n == nil || (n != node && len(classesWithPrefix(n, "h-")) > 0) {
+			break
+		}
+		switch n.DataAtom {
+		case atom.Img:
+			if src, ok := attr(n, "src"); ok {
+				return src
+			}
+		case atom.Object:
+			if data, ok := attr(n, "data"); ok {
+				return data
+			}
+		}
+	}
+	return ""
+}
+
+// impliedURL returns the url of a microformat
+// without a u-url or other url properties.
+func impliedURL(node *html.Node) string {
+	for _, n := range []*html.Node{node, onlyChild(node), onlyChild(onlyChild(node))} {
+		if n == nil || (n != node && len(classesWithPrefix(n, "h-")) > 0) {
+			break
+		}
+		if n.DataAtom == atom.A || n.DataAtom == atom.Area {
+			if href, ok := attr(n, "href"); ok {
+				return href
+			}
+		}
+	}
+	return ""
+}
+
+// onlyChild returns the only child element of a node,
+// or nil if it has no or several child elements.
+func onlyChild(node *html.Node) (only *html.Node) {
+	if node == nil {
+		return nil
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if only != nil {
+			return nil
+		}
+		only = c
+	}
+	return only
+}
+
+// textContent returns the text of a node, with images
+// replaced by their alt text and without scripts and styles.
+func textContent(node *html.Node) string {
+	var b strings.Builder
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.DataAtom == atom.Script, n.DataAtom == atom.Style, n.DataAtom == atom.Template:
+		case n.DataAtom == atom.Img:
+			if alt, ok := attr(n, "alt"); ok {
+				b.WriteString(" " + alt + " ")
+			}
+		default:
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				visit(c)
+			}
+		}
+	}
+	visit(node)
+	return b.String()
+}
+
+// collapse trims text and turns each run of
+// whitespace within it into a single space.
+func collapse(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func attr(node *html.Node, name string) (string, bool) {
+	for _, a := range node.Attr {
+		if a.Namespace == "" && a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// classesWithPrefix returns the names of the classes of a
+// node which are microformats2 classes with the prefix,
+// without the prefix for properties.
+func classesWithPrefix(node *html.Node, prefix string) (names []string) {
+	class, _ := attr(node, "class")
+	for _, c := range strings.Fields(class) {
+		if !strings.HasPrefix(c, prefix) || !isName(c[len(prefix):]) {
+			continue
+		}
+		if prefix != "h-" {
+			c = c[len(prefix):]
+		}
+		names = append(names, c)
+	}
+	return
+}
+
+// isName reports whether s is a valid microformats2
+// name: lowercase letters and digits joined by dashes.
+func isName(s string) bool {
+	if s == "" || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// findRoot returns the first element of the
+// document which has the class, or nil.
+func findRoot(node *html.Node, class string) *html.Node {
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		for _, typ := range classesWithPrefix(c, "h-") {
+			if typ == class {
+				return c
+			}
+		}
+		if found := findRoot(c, class); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findElement returns the first element of the
+// document with the given tag, or nil.
+func findElement(node *html.Node, tag atom.Atom) *html.Node {
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.DataAtom == tag {
+			return c
+		}
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func documentTitle(doc *html.Node) string {
+	if title := findElement(doc, atom.Title); title != nil {
+		return collapse(textContent(title))
+	}
+	return ""
+}
+
+// documentBase returns the absolute URL of the <base>
+// element of the document, or nil.
+func documentBase(doc *html.Node) *url.URL {
+	base := findElement(doc, atom.Base)
+	if base == nil {
+		return nil
+	}
+	href, _ := attr(base, "href")
+	if u, err := url.Parse(strings.TrimSpace(href)); err == nil && u.IsAbs() {
+		return u
+	}
+	return nil
+}
+
+// language returns the lang of the closest
+// element to node which has one.
+func language(node *html.Node) string {
+	for n := node; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if lang, ok := attr(n, "lang"); ok {
+			return strings.TrimSpace(lang)
+		}
+	}
+	return ""
+}
+
+func (p *parser) resolve(ref string) string {
+	return shared.ResolveURL(p.base, ref)
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
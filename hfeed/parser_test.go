@@ -0,0 +1,74 @@
+package hfeed_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed/hfeed"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests
+
+func TestParser_Parse(t *testing.T) {
+	files, _ := filepath.Glob("../testdata/parser/hfeed/*.html")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("../testdata/parser/hfeed/%s.html", name)
+		f, _ := os.ReadFile(ff)
+
+		// Parse actual feed
+		fp := &hfeed.Parser{}
+		actual, _ := fp.Parse(bytes.NewReader(f))
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("../testdata/parser/hfeed/%s_expected.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &hfeed.Feed{}
+		json.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.html did not match expected output %s_expected.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestParser_ParseNoFeed(t *testing.T) {
+	f, _ := os.ReadFile("../testdata/parser/hfeed/invalid/no_feed.html")
+
+	fp := &hfeed.Parser{}
+	feed, err := fp.Parse(bytes.NewReader(f))
+	assert.Nil(t, feed)
+	assert.Equal(t, hfeed.ErrNoFeed, err)
+}
+
+func TestParser_ParseWithContext(t *testing.T) {
+	feedData := `<div class="h-feed"><div class="h-entry"><p class="p-name">Entry</p></div></div>`
+
+	fp := &hfeed.Parser{}
+	feed, err := fp.ParseWithContext(strings.NewReader(feedData), context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Entry", feed.Entries[0].Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	feed, err = fp.ParseWithContext(strings.NewReader(feedData), ctx)
+	assert.Nil(t, feed)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,69 @@
+package gofeed
+
+import (
+	"github.com/mmcdole/gofeed/atom"
+	"github.com/mmcdole/gofeed/json"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// FeedHook post-processes a translated feed.  native is the
+// feed as its format's parser returned it: an *rss.Feed,
+// *atom.Feed or *json.Feed.
+type FeedHook func(native interface{}, feed *Feed) error
+
+// ItemHook post-processes a translated item.  native is the
+// item it was translated from: an *rss.Item, *atom.Entry or
+// *json.Item.  It is nil for the formats added with
+// RegisterFormat and if the translator didn't translate the
+// items of the feed one for one and in order.
+type ItemHook func(native interface{}, item *Item) error
+
+// runHooks runs the item hooks of the parser on each item
+// of a translated feed and then its feed hooks on the feed.
+// It stops at the first hook which returns an error.
+func (f *Parser) runHooks(native interface{}, feed *Feed) error {
+	if len(f.ItemHooks) > 0 {
+		items := nativeItems(native)
+		if len(items) != len(feed.Items) {
+			items = nil
+		}
+		for i, item := range feed.Items {
+			var nativeItem interface{}
+			if items != nil {
+				nativeItem = items[i]
+			}
+			for _, hook := range f.ItemHooks {
+				if err := hook(nativeItem, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, hook := range f.FeedHooks {
+		if err := hook(native, feed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nativeItems returns the items of a feed as
+// its format's parser returned it.
+func nativeItems(native interface{}) (items []interface{}) {
+	switch feed := native.(type) {
+	case *rss.Feed:
+		for _, item := range feed.Items {
+			items = append(items, item)
+		}
+	case *atom.Feed:
+		for _, entry := range feed.Entries {
+			items = append(items, entry)
+		}
+	case *json.Feed:
+		for _, item := range feed.Items {
+			items = append(items, item)
+		}
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package gofeed_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Hooks(t *testing.T) {
+	feedData := `<rss version="2.0"><channel><title>Title</title>
+		<item><guid>1</guid><title>One</title></item>
+		<item><guid>2</guid><title>Two</title></item>
+	</channel></rss>`
+
+	var calls []string
+	fp := gofeed.NewParser()
+	fp.ItemHooks = []gofeed.ItemHook{
+		func(native interface{}, item *gofeed.Item) error {
+			rssItem := native.(*rss.Item)
+			item.Custom = map[string]string{"guid": rssItem.GUID.Value}
+			calls = append(calls, "item "+item.Title)
+			return nil
+		},
+	}
+	fp.FeedHooks = []gofeed.FeedHook{
+		func(native interface{}, feed *gofeed.Feed) error {
+			feed.Title = native.(*rss.Feed).Title + " (fixed)"
+			calls = append(calls, "feed")
+			return nil
+		},
+	}
+
+	feed, err := fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"item One", "item Two", "feed"}, calls)
+	assert.Equal(t, "Title (fixed)", feed.Title)
+	assert.Equal(t, "2", feed.Items[1].Custom["guid"])
+
+	hookErr := errors.New("hook failed")
+	fp.FeedHooks = append(fp.FeedHooks, func(native interface{}, feed *gofeed.Feed) error {
+		return hookErr
+	})
+	feed, err = fp.ParseString(feedData)
+	assert.Equal(t, hookErr, err)
+	assert.Nil(t, feed)
+}
+
+type firstItemTranslator struct{}
+
+func (firstItemTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	return &gofeed.Feed{Items: []*gofeed.Item{{Title: "only"}}}, nil
+}
+
+func TestParser_HooksCustomTranslator(t *testing.T) {
+	fp := gofeed.NewParser()
+	fp.RSSTranslator = firstItemTranslator{}
+
+	var natives []interface{}
+	fp.ItemHooks = []gofeed.ItemHook{
+		func(native interface{}, item *gofeed.Item) error {
+			natives = append(natives, native)
+			return nil
+		},
+	}
+
+	_, err := fp.ParseString(`<rss version="2.0"><channel><item/><item/></channel></rss>`)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil}, natives)
+}
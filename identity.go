@@ -0,0 +1,112 @@
+package gofeed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Prefixes of the keys returned by Item.Identity,
+// naming the field the identity was taken from.
+const (
+	IdentityGUID      = "guid:"
+	IdentityLink      = "link:"
+	IdentityEnclosure = "enclosure:"
+	IdentityHash      = "hash:"
+)
+
+// TrackingParams are the query parameters removed by NormalizeURL.
+// Parameters starting with "utm_" are always removed.
+var TrackingParams = []string{
+	"fbclid", "gclid", "dclid", "msclkid", "yclid", "igshid",
+	"mc_cid", "mc_eid", "_hsenc", "_hsmi", "mkt_tok",
+	"ref_src", "ref_url", "__twitter_impression",
+}
+
+// Identity returns a key which identifies the item across fetches
+// of its feed.  It is the first of the item's GUID (the id of Atom
+// and JSON items), its normalized link, the normalized URL of its
+// first enclosure and its fingerprint, prefixed with the name of
+// the one that was used (see IdentityGUID and friends).
+func (i *Item) Identity() string {
+	if guid := strings.TrimSpace(i.GUID); guid != "" {
+		return IdentityGUID + guid
+	}
+	if link := NormalizeURL(i.Link); link != "" {
+		return IdentityLink + link
+	}
+	for _, enc := range i.Enclosures {
+		if enc == nil {
+			continue
+		}
+		if u := NormalizeURL(enc.URL); u != "" {
+			return IdentityEnclosure + u
+		}
+	}
+	return IdentityHash + i.Fingerprint()
+}
+
+// Fingerprint returns a hash of the item's title, published date
+// and content, or description if it has no content.  Markup, case
+// and whitespace are ignored so that the fingerprint survives
+// cosmetic changes.  The updated date isn't included as it changes
+// whenever the item is edited.
+func (i *Item) Fingerprint() string {
+	date := i.Published
+	if i.PublishedParsed != nil {
+		date = i.PublishedParsed.UTC().Format(time.RFC3339)
+	}
+	content := i.Content
+	if content == "" {
+		content = i.Description
+	}
+
+	h := sha256.New()
+	for _, s := range []string{HTMLToText(i.Title), date, HTMLToText(content)} {
+		h.Write([]byte(strings.ToLower(strings.Join(strings.Fields(s), " "))))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NormalizeURL returns a canonical form of an absolute URL for use
+// in comparisons.  The scheme and host are lower-cased, default
+// ports, fragments and TrackingParams are removed, the remaining
+// query parameters are sorted and an empty path becomes "/".
+// Relative or unparsable URLs are only trimmed of whitespace.
+func NormalizeURL(u string) string {
+	u = strings.TrimSpace(u)
+	parsed, err := url.Parse(u)
+	if err != nil || !parsed.IsAbs() || parsed.Opaque != "" {
+		return u
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if port := parsed.Port(); (parsed.Scheme == "http" && port == "80") ||
+		(parsed.Scheme == "https" && port == "443") {
+		parsed.Host = parsed.Hostname()
+	}
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	parsed.Fragment, parsed.RawFragment = "", ""
+
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for name := range query {
+			if isTrackingParam(name) {
+				query.Del(name)
+			}
+		}
+		parsed.RawQuery = query.Encode()
+	}
+	return parsed.String()
+}
+
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "utm_") || contains(TrackingParams, name)
+}
@@ -0,0 +1,77 @@
+package gofeed_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"", ""},
+		{" HTTP://Example.ORG ", "http://example.org/"},
+		{"https://example.org:443/Post?id=1#comments", "https://example.org/Post?id=1"},
+		{"http://example.org:8080/", "http://example.org:8080/"},
+		{"http://example.org/?utm_source=rss&utm_medium=feed&b=2&a=1", "http://example.org/?a=1&b=2"},
+		{"http://example.org/?fbclid=x&GCLID=y", "http://example.org/"},
+		{"/relative/path", "/relative/path"},
+		{"mailto:Me@Example.org", "mailto:Me@Example.org"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.out, gofeed.NormalizeURL(test.in), "normalizing %q", test.in)
+	}
+}
+
+func TestItem_Identity(t *testing.T) {
+	enclosure := []*gofeed.Enclosure{{URL: "http://example.org/1.mp3?utm_source=rss"}}
+
+	tests := []struct {
+		item *gofeed.Item
+		want string
+	}{
+		{&gofeed.Item{GUID: " urn:1 ", Link: "http://example.org/1"}, "guid:urn:1"},
+		{&gofeed.Item{Link: "HTTP://EXAMPLE.org/1?utm_campaign=x", Enclosures: enclosure}, "link:http://example.org/1"},
+		{&gofeed.Item{Enclosures: append([]*gofeed.Enclosure{nil}, enclosure...)}, "enclosure:http://example.org/1.mp3"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.item.Identity())
+	}
+
+	item := &gofeed.Item{Title: "Hello"}
+	assert.True(t, strings.HasPrefix(item.Identity(), gofeed.IdentityHash))
+	assert.Equal(t, gofeed.IdentityHash+item.Fingerprint(), item.Identity())
+}
+
+func TestItem_Fingerprint(t *testing.T) {
+	published := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	item := &gofeed.Item{
+		Title:           "Hello World",
+		Content:         "<p>Some content</p>",
+		PublishedParsed: &published,
+	}
+
+	// Cosmetic changes keep the fingerprint
+	same := &gofeed.Item{
+		Title:           " hello  world",
+		Content:         "<div>Some\n content</div>",
+		PublishedParsed: &[]time.Time{published.In(time.FixedZone("X", 3600))}[0],
+		UpdatedParsed:   &[]time.Time{time.Now()}[0],
+	}
+	assert.Equal(t, item.Fingerprint(), same.Fingerprint())
+
+	other := &gofeed.Item{Title: "Hello World", Content: "<p>Other content</p>", PublishedParsed: &published}
+	assert.NotEqual(t, item.Fingerprint(), other.Fingerprint())
+
+	// Fields don't run into each other
+	a := &gofeed.Item{Title: "ab", Description: "c"}
+	b := &gofeed.Item{Title: "a", Description: "bc"}
+	assert.NotEqual(t, a.Fingerprint(), b.Fingerprint())
+}
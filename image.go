@@ -0,0 +1,124 @@
+package gofeed
+
+import (
+	"strings"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// ImageSource is a place the image of an item can be taken from.
+type ImageSource string
+
+// Sources of Item.Image.
+const (
+	// ImageSourceItem is the image the feed format gives items
+	// of its own, such as the image and banner_image of JSON
+	// Feed items.
+	ImageSourceItem ImageSource = "item"
+	// ImageSourceITunes is the itunes:image of the item.
+	ImageSourceITunes ImageSource = "itunes"
+	// ImageSourceMediaContent is the first media:content which
+	// is an image, on its own or in a media:group.
+	ImageSourceMediaContent ImageSource = "media:content"
+	// ImageSourceMediaThumbnail is the first media:thumbnail,
+	// on its own or in a media:group.
+	ImageSourceMediaThumbnail ImageSource = "media:thumbnail"
+	// ImageSourceEnclosure is the first enclosure with
+	// an image type.
+	ImageSourceEnclosure ImageSource = "enclosure"
+	// ImageSourceContent and ImageSourceDescription are the
+	// first <img> in the Content and Description of the item.
+	ImageSourceContent     ImageSource = "content"
+	ImageSourceDescription ImageSource = "description"
+)
+
+// DefaultImageSources returns the order in which the
+// default translators look for the image of an item.
+func DefaultImageSources() []ImageSource {
+	return []ImageSource{
+		ImageSourceItem,
+		ImageSourceITunes,
+		ImageSourceMediaContent,
+		ImageSourceMediaThumbnail,
+		ImageSourceEnclosure,
+		ImageSourceContent,
+		ImageSourceDescription,
+	}
+}
+
+// selectImage returns the image of a translated item from the
+// first of the sources which has one.  own is the image of
+// the item in its feed format, for ImageSourceItem.  A nil
+// sources uses DefaultImageSources.
+func selectImage(item *Item, own *Image, sources []ImageSource) *Image {
+	if sources == nil {
+		sources = DefaultImageSources()
+	}
+	for _, source := range sources {
+		if image := imageFrom(item, own, source); image != nil {
+			return image
+		}
+	}
+	return nil
+}
+
+func imageFrom(item *Item, own *Image, source ImageSource) *Image {
+	switch source {
+	case ImageSourceItem:
+		if own != nil && own.URL != "" {
+			return own
+		}
+	case ImageSourceITunes:
+		if item.ITunesExt != nil && item.ITunesExt.Image != "" {
+			return &Image{URL: item.ITunesExt.Image}
+		}
+	case ImageSourceMediaContent:
+		for _, c := range mediaElements(item.Extensions, "content") {
+			if strings.Contains(c.Attrs["type"], "image") || strings.Contains(c.Attrs["medium"], "image") {
+				return mediaImage(c)
+			}
+		}
+	case ImageSourceMediaThumbnail:
+		for _, thumb := range mediaElements(item.Extensions, "thumbnail") {
+			if thumb.Attrs["url"] != "" {
+				return mediaImage(thumb)
+			}
+		}
+	case ImageSourceEnclosure:
+		for _, enc := range item.Enclosures {
+			if enc != nil && strings.HasPrefix(enc.Type, "image/") {
+				return &Image{URL: enc.URL}
+			}
+		}
+	case ImageSourceContent:
+		return firstImageFromHtmlDocument(item.Content)
+	case ImageSourceDescription:
+		return firstImageFromHtmlDocument(item.Description)
+	}
+	return nil
+}
+
+// mediaImage returns the image of a media:content
+// or media:thumbnail element.
+func mediaImage(e ext.Extension) *Image {
+	return &Image{
+		URL:    e.Attrs["url"],
+		Width:  parseDimension(e.Attrs["width"]),
+		Height: parseDimension(e.Attrs["height"]),
+	}
+}
+
+// mediaElements returns the Media RSS elements of an item with
+// the given name, those of the item first and then those in
+// its media:group elements.
+func mediaElements(extensions ext.Extensions, name string) []ext.Extension {
+	media, ok := extensions["media"]
+	if !ok {
+		return nil
+	}
+	elements := append([]ext.Extension{}, media[name]...)
+	for _, group := range media["group"] {
+		elements = append(elements, group.Children[name]...)
+	}
+	return elements
+}
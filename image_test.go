@@ -0,0 +1,61 @@
+package gofeed_test
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageSources(t *testing.T) {
+	jsonFeed := &json.Feed{Items: []*json.Item{{
+		Image:       "http://example.org/image.jpg",
+		ContentHTML: `<p><img src="http://example.org/content.jpg"></p>`,
+		Attachments: &[]json.Attachments{
+			{URL: "http://example.org/episode.mp3", MimeType: "audio/mpeg"},
+			{URL: "http://example.org/cover.png", MimeType: "image/png"},
+		},
+	}}}
+
+	tests := []struct {
+		sources []gofeed.ImageSource
+		image   string
+	}{
+		{nil, "http://example.org/image.jpg"},
+		{[]gofeed.ImageSource{gofeed.ImageSourceEnclosure, gofeed.ImageSourceItem}, "http://example.org/cover.png"},
+		{[]gofeed.ImageSource{gofeed.ImageSourceITunes, gofeed.ImageSourceContent}, "http://example.org/content.jpg"},
+		{[]gofeed.ImageSource{gofeed.ImageSourceMediaThumbnail}, ""},
+		{[]gofeed.ImageSource{}, ""},
+	}
+
+	for _, test := range tests {
+		translator := &gofeed.DefaultJSONTranslator{TranslatorOptions: gofeed.TranslatorOptions{ImageSources: test.sources}}
+		feed, err := translator.Translate(jsonFeed)
+		assert.Nil(t, err)
+
+		image := feed.Items[0].Image
+		if test.image == "" {
+			assert.Nil(t, image, "%v", test.sources)
+		} else if assert.NotNil(t, image, "%v", test.sources) {
+			assert.Equal(t, test.image, image.URL, "%v", test.sources)
+		}
+	}
+}
+
+func TestImageSources_Parser(t *testing.T) {
+	feedData := `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><item>
+		<description>&lt;img src="http://example.org/description.jpg"&gt;</description>
+		<media:thumbnail url="http://example.org/thumbnail.jpg"/>
+	</item></channel></rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.org/thumbnail.jpg", feed.Items[0].Image.URL)
+
+	fp.ImageSources = []gofeed.ImageSource{gofeed.ImageSourceDescription}
+	feed, err = fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.org/description.jpg", feed.Items[0].Image.URL)
+}
@@ -13,7 +13,28 @@ func NewReaderLabel(label string, input io.Reader) (io.Reader, error) {
 		return nil, err
 	}
 
-	// Wrap the charset decoder reader with a XML sanitizer
-	//clean := NewXMLSanitizerReader(conv)
 	return conv, nil
 }
+
+// NewSanitizingReaderLabel is like NewReaderLabel but also
+// removes characters which are illegal in XML as the input
+// is converted.
+func NewSanitizingReaderLabel(label string, input io.Reader) (io.Reader, error) {
+	conv, err := NewReaderLabel(label, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap the charset decoder reader with a XML sanitizer
+	clean := NewXMLSanitizerReader(conv)
+	return clean, nil
+}
+
+// CharsetReader returns the charset reader of an XML
+// pull parser, which strips illegal characters if asked.
+func CharsetReader(stripIllegalChars bool) func(string, io.Reader) (io.Reader, error) {
+	if stripIllegalChars {
+		return NewSanitizingReaderLabel
+	}
+	return NewReaderLabel
+}
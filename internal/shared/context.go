@@ -0,0 +1,32 @@
+package shared
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader fails with the error of its
+// context once the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// NewContextReader returns a reader which reads from r until
+// ctx is done, and from then on fails with the context's error.
+// A read which is blocked when the context is done isn't
+// interrupted; the next one fails.
+func NewContextReader(ctx context.Context, r io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		// The context can never be cancelled
+		return r
+	}
+	return &contextReader{ctx: ctx, r: r}
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
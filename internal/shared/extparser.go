@@ -162,9 +162,11 @@ var canonicalNamespaces = map[string]string{
 	"http://schemas.pocketsoap.com/rss/myDescModule/":                "szf",
 	"http://purl.org/rss/1.0/modules/taxonomy/":                      "taxo",
 	"http://purl.org/rss/1.0/modules/threading/":                     "thr",
+	"http://purl.org/syndication/thread/1.0":                         "thr",
 	"http://purl.org/rss/1.0/modules/textinput/":                     "ti",
 	"http://madskills.com/public/xml/rss/module/trackback/":          "trackback",
 	"http://wellformedweb.org/commentAPI/":                           "wfw",
+	"http://wellformedweb.org/CommentAPI/":                           "wfw",
 	"http://purl.org/rss/1.0/modules/wiki/":                          "wiki",
 	"http://www.w3.org/1999/xhtml":                                   "xhtml",
 	"http://www.w3.org/1999/xlink":                                   "xlink",
@@ -0,0 +1,282 @@
+package shared
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	// HTML elements which never have content.  Elements such as
+	// link and source are deliberately missing as they carry
+	// text in RSS.
+	voidElements = map[string]bool{
+		"area":  true,
+		"br":    true,
+		"col":   true,
+		"embed": true,
+		"hr":    true,
+		"img":   true,
+		"input": true,
+		"meta":  true,
+		"param": true,
+		"track": true,
+		"wbr":   true,
+	}
+
+	// ErrNoElements is returned by RebuildXML if the document
+	// does not contain a single element.
+	ErrNoElements = errors.New("document does not contain any elements")
+
+	xmlDeclRgx  = regexp.MustCompile(`^\s*<\?xml[^>]*>`)
+	encodingRgx = regexp.MustCompile(`(encoding\s*=\s*)(?:"[^"]*"|'[^']*')`)
+)
+
+// RebuildXML re-reads a malformed XML document with the forgiving
+// HTML5 tokenizer and writes it back out as well formed XML.
+//
+// An end tag which doesn't match any open element closes the
+// current element if that element only holds text (a misspelt
+// end tag), and is ignored otherwise.  Elements left open are
+// closed when an enclosing element ends (or at the end of the
+// document), text outside the root element is dropped, and all
+// text and attribute values are escaped again.  Element and
+// attribute names keep the case they were written in, which the
+// tokenizer loses, as extension elements such as sy:updatePeriod
+// are matched by their exact names.
+//
+// The document is converted to UTF-8 from the encoding of its XML
+// declaration, which is rewritten to match.
+func RebuildXML(data []byte) ([]byte, error) {
+	data = toUTF8(data)
+	z := xhtml.NewTokenizer(bytes.NewReader(data))
+	z.AllowCDATA(true)
+
+	var out bytes.Buffer
+	var stack []openElement
+	elements := 0
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			if z.Err() != io.EOF {
+				return nil, z.Err()
+			}
+			closeElements(&out, &stack, 0)
+			if elements == 0 {
+				return nil, ErrNoElements
+			}
+			return out.Bytes(), nil
+
+		case xhtml.TextToken:
+			if len(stack) == 0 {
+				// Text outside the root element isn't
+				// allowed in XML, and feeds don't need it.
+				continue
+			}
+			raw := z.Raw()
+			if bytes.HasPrefix(raw, []byte("<![CDATA[")) {
+				out.Write(raw)
+				if !bytes.HasSuffix(raw, []byte("]]>")) {
+					out.WriteString("]]>")
+				}
+			} else {
+				out.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case xhtml.CommentToken:
+			// Keep the XML declaration so that the
+			// document's encoding is still known.
+			raw := z.Raw()
+			if out.Len() == 0 && bytes.HasPrefix(raw, []byte("<?xml")) {
+				out.Write(encodingRgx.ReplaceAll(raw, []byte(`${1}"UTF-8"`)))
+			}
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			// Feed elements such as <title> must never be
+			// treated as raw text, they may contain markup.
+			z.NextIsNotRawText()
+
+			name, attrs := rebuildTag(z)
+			if name == "" {
+				continue
+			}
+			elements++
+			if len(stack) > 0 {
+				stack[len(stack)-1].hasChildren = true
+			}
+
+			out.WriteString("<" + name + attrs)
+			if tt == xhtml.SelfClosingTagToken || voidElements[name] {
+				out.WriteString("/>")
+			} else {
+				out.WriteString(">")
+				stack = append(stack, openElement{name: name})
+			}
+
+		case xhtml.EndTagToken:
+			name, _ := rawNames(z.Raw())
+			if !isXMLName(name) {
+				// The start tag was dropped too
+				continue
+			}
+			matched := false
+			for i := len(stack) - 1; i >= 0; i-- {
+				if strings.EqualFold(stack[i].name, name) {
+					closeElements(&out, &stack, i)
+					matched = true
+					break
+				}
+			}
+			if !matched && len(stack) > 0 && !stack[len(stack)-1].hasChildren {
+				closeElements(&out, &stack, len(stack)-1)
+			}
+		}
+	}
+}
+
+// toUTF8 converts a document to UTF-8 from the encoding of its
+// XML declaration.  Documents in UTF-8 or an unknown encoding
+// are returned unchanged.
+func toUTF8(data []byte) []byte {
+	decl := xmlDeclRgx.Find(data)
+	m := encodingRgx.FindSubmatch(decl)
+	if m == nil {
+		return data
+	}
+	label := strings.Trim(string(m[0][len(m[1]):]), `"'`)
+	if strings.EqualFold(label, "utf-8") {
+		return data
+	}
+	r, err := charset.NewReaderLabel(label, bytes.NewReader(data))
+	if err != nil {
+		return data
+	}
+	converted, err := io.ReadAll(r)
+	if err != nil {
+		return data
+	}
+	return converted
+}
+
+type openElement struct {
+	name        string
+	hasChildren bool
+}
+
+// closeElements writes end tags for the open elements
+// from the top of the stack down to (and including) the
+// element at index i.
+func closeElements(out *bytes.Buffer, stack *[]openElement, i int) {
+	for j := len(*stack) - 1; j >= i; j-- {
+		out.WriteString("</" + (*stack)[j].name + ">")
+	}
+	*stack = (*stack)[:i]
+}
+
+// rebuildTag returns the name and serialized attributes of
+// the current tag, dropping anything which isn't a valid XML
+// name.
+func rebuildTag(z *xhtml.Tokenizer) (string, string) {
+	// The tokenizer lower-cases the raw tag in place
+	name, attrNames := rawNames(z.Raw())
+	_, hasAttr := z.TagName()
+	if !isXMLName(name) {
+		return "", ""
+	}
+
+	var attrs strings.Builder
+	seen := map[string]bool{}
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = z.TagAttr()
+		k := string(key)
+		if original, ok := attrNames[k]; ok {
+			k = original
+		}
+		if !isXMLName(k) || seen[strings.ToLower(k)] {
+			continue
+		}
+		seen[strings.ToLower(k)] = true
+		attrs.WriteString(" " + k + `="` + html.EscapeString(string(val)) + `"`)
+	}
+	return name, attrs.String()
+}
+
+// rawNames returns the name of a raw start or end tag and the
+// names of its attributes, keyed by their lower-cased names, in
+// the case they were written in.
+func rawNames(raw []byte) (string, map[string]string) {
+	isSpace := func(b byte) bool {
+		return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+	}
+	i := 1
+	if i < len(raw) && raw[i] == '/' {
+		i++
+	}
+	start := i
+	for i < len(raw) && !isSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' {
+		i++
+	}
+	name := string(raw[start:i])
+
+	attrs := map[string]string{}
+	for i < len(raw) {
+		for i < len(raw) && (isSpace(raw[i]) || raw[i] == '/') {
+			i++
+		}
+		if i >= len(raw) || raw[i] == '>' {
+			break
+		}
+		start = i
+		for i < len(raw) && !isSpace(raw[i]) && raw[i] != '=' && raw[i] != '/' && raw[i] != '>' {
+			i++
+		}
+		attr := string(raw[start:i])
+		if _, ok := attrs[strings.ToLower(attr)]; !ok {
+			attrs[strings.ToLower(attr)] = attr
+		}
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i >= len(raw) || raw[i] != '=' {
+			continue
+		}
+		i++
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i < len(raw) && (raw[i] == '"' || raw[i] == '\'') {
+			quote := raw[i]
+			i++
+			for i < len(raw) && raw[i] != quote {
+				i++
+			}
+			i++
+		} else {
+			for i < len(raw) && !isSpace(raw[i]) && raw[i] != '>' {
+				i++
+			}
+		}
+	}
+	return name, attrs
+}
+
+func isXMLName(name string) bool {
+	if name == "" || strings.Count(name, ":") > 1 || strings.HasSuffix(name, ":") {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isNameByte(name[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildXML(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"<rss><channel></channel></rss>", "<rss><channel></channel></rss>"},
+		{`<?xml version="1.0" encoding="ISO-8859-1"?><rss/>`, `<?xml version="1.0" encoding="UTF-8"?><rss/>`},
+		{"<?xml version='1.0' encoding='windows-1252'?><a>caf\xe9 &#233;</a>", `<?xml version='1.0' encoding="UTF-8"?><a>café é</a>`},
+		{"<item><title>a</titel></item>", "<item><title>a</title></item>"},
+		{"<item><title>a</titel><link>b</link></item>", "<item><title>a</title><link>b</link></item>"},
+		{"<item><description>a<b>b</x>c</description></item>", "<item><description>a<b>b</b>c</description></item>"},
+		{"<item><description>unclosed</item>", "<item><description>unclosed</description></item>"},
+		{"<item><description>a<br>b</description></item>", "<item><description>a<br/>b</description></item>"},
+		{"<item><title>Fish & Chips</title>", "<item><title>Fish &amp; Chips</title></item>"},
+		{"<item><title><![CDATA[<b>&amp;</b>]]></title></item>", "<item><title><![CDATA[<b>&amp;</b>]]></title></item>"},
+		{`<Feed xmlns="http://www.w3.org/2005/Atom"><Link HREF="a?b&c" href="x"/></Feed>`, `<Feed xmlns="http://www.w3.org/2005/Atom"><Link HREF="a?b&amp;c"/></Feed>`},
+		{`<channel><sy:updatePeriod>daily</sy:UPDATEPERIOD><cloud registerProcedure='p' a=b /></channel>`, `<channel><sy:updatePeriod>daily</sy:updatePeriod><cloud registerProcedure="p" a="b"/></channel>`},
+		{"<content:encoded>x</content:encoded>", "<content:encoded>x</content:encoded>"},
+		{"<!-- comment --><rss></rss>", "<rss></rss>"},
+		{"<a>x <b:c:d>y</b:c:d> z</a>", "<a>x y z</a>"},
+		{"text <a>x</a> more", "<a>x</a>"},
+	}
+
+	for _, test := range tests {
+		out, err := RebuildXML([]byte(test.in))
+		assert.Nil(t, err, "rebuilding %q", test.in)
+		assert.Equal(t, test.out, string(out), "rebuilding %q", test.in)
+	}
+}
+
+func TestRebuildXML_NoElements(t *testing.T) {
+	out, err := RebuildXML([]byte("just some text"))
+	assert.Nil(t, out)
+	assert.Equal(t, ErrNoElements, err)
+}
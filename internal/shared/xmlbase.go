@@ -41,6 +41,12 @@ var (
 	}
 )
 
+// IsHTMLURIAttr reports whether the
+// HTML attribute name holds a URI.
+func IsHTMLURIAttr(name string) bool {
+	return htmlURIAttrs[name]
+}
+
 // XMLBase.NextTag iterates through the tokens until it reaches a StartTag or
 // EndTag. It resolves urls in tag attributes relative to the current xml:base.
 //
@@ -166,3 +172,91 @@ func ResolveHTML(base *url.URL, relHTML string) (string, error) {
 
 	return absHTML, err
 }
+
+// ResolveURL resolves a possibly relative URL against base.  The
+// URL is returned unchanged if there is no base, if it is already
+// absolute or if it can't be parsed.
+func ResolveURL(base *url.URL, u string) string {
+	if base == nil || u == "" {
+		return u
+	}
+	ref, err := url.Parse(strings.TrimSpace(u))
+	if err != nil || ref.IsAbs() {
+		return u
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// ResolveHTMLURLs resolves the relative URIs in the attributes of
+// an HTML fragment against base.  Unlike ResolveHTML the fragment
+// isn't re-rendered, only tags holding relative URIs are rewritten.
+func ResolveHTMLURLs(base *url.URL, fragment string) string {
+	if base == nil || !strings.Contains(fragment, "<") {
+		return fragment
+	}
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	var out strings.Builder
+	changed := false
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		// Raw must be copied before TagName lower-cases it in place
+		raw := string(z.Raw())
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.WriteString(raw)
+			continue
+		}
+
+		name, hasAttr := z.TagName()
+		tag := "<" + string(name)
+		resolved := false
+		for hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			v := string(val)
+			if htmlURIAttrs[string(key)] {
+				if abs := ResolveURL(base, v); abs != v {
+					v, resolved = abs, true
+				}
+			}
+			tag += " " + string(key) + `="` + html.EscapeString(v) + `"`
+		}
+		if !resolved {
+			out.WriteString(raw)
+			continue
+		}
+		if tt == html.SelfClosingTagToken {
+			tag += "/"
+		}
+		out.WriteString(tag + ">")
+		changed = true
+	}
+
+	if !changed {
+		return fragment
+	}
+	return out.String()
+}
+
+// XMLBase returns the base URL in scope at the current start tag,
+// which is parent resolved against the tag's xml:base attribute.
+func XMLBase(p *xpp.XMLPullParser, parent *url.URL) *url.URL {
+	for _, attr := range p.Attrs {
+		if attr.Name.Local != "base" || attr.Name.Space != "http://www.w3.org/XML/1998/namespace" {
+			continue
+		}
+		base, err := url.Parse(strings.TrimSpace(attr.Value))
+		if err != nil {
+			return parent
+		}
+		if parent != nil {
+			base = parent.ResolveReference(base)
+		}
+		return base
+	}
+	return parent
+}
@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveURL(t *testing.T) {
+	base, _ := url.Parse("http://example.org/blog/post.html")
+
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"", ""},
+		{"other.html", "http://example.org/blog/other.html"},
+		{"/logo.png", "http://example.org/logo.png"},
+		{"#top", "http://example.org/blog/post.html#top"},
+		{"//cdn.example.org/a.js", "http://cdn.example.org/a.js"},
+		{"https://example.com/", "https://example.com/"},
+		{"mailto:me@example.org", "mailto:me@example.org"},
+		{"%zz", "%zz"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.out, ResolveURL(base, test.in), "resolving %q", test.in)
+	}
+	assert.Equal(t, "other.html", ResolveURL(nil, "other.html"))
+}
+
+func TestResolveHTMLURLs(t *testing.T) {
+	base, _ := url.Parse("http://example.org/blog/")
+
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"plain text", "plain text"},
+		{`<p class="x">Hi &amp; bye</p>`, `<p class="x">Hi &amp; bye</p>`},
+		{`<a href="http://example.com/">x</a>`, `<a href="http://example.com/">x</a>`},
+		{`<a href="post.html" title="A &amp; B">x</a>`, `<a href="http://example.org/blog/post.html" title="A &amp; B">x</a>`},
+		{`<P><IMG SRC="/a.png"/></P>`, `<P><img src="http://example.org/a.png"/></P>`},
+		{`<video poster="p.jpg" src="v.mp4"></video>`, `<video poster="http://example.org/blog/p.jpg" src="http://example.org/blog/v.mp4"></video>`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.out, ResolveHTMLURLs(base, test.in))
+	}
+	assert.Equal(t, `<a href="x">`, ResolveHTMLURLs(nil, `<a href="x">`))
+}
@@ -1,7 +1,11 @@
 package shared
 
 import (
+	"bytes"
+	"fmt"
+	"html"
 	"io"
+	"unicode/utf8"
 
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
@@ -13,14 +17,380 @@ import (
 func NewXMLSanitizerReader(xml io.Reader) io.Reader {
 	isIllegal := runes.Predicate(func() func(rune) bool {
 		return func(r rune) bool {
-			return !(r == 0x09 ||
-				r == 0x0A ||
-				r == 0x0D ||
-				r >= 0x20 && r <= 0xDF77 ||
-				r >= 0xE000 && r <= 0xFFFD ||
-				r >= 0x10000 && r <= 0x10FFFF)
+			return !isXMLChar(r)
 		}
 	}())
 	t := transform.Chain(runes.Remove(isIllegal))
 	return transform.NewReader(xml, t)
 }
+
+// isXMLChar reports whether r is in the Char production
+// of the XML 1.0 specification.
+func isXMLChar(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+// Repair kinds reported by RepairXML.
+const (
+	RepairIllegalChar   = "illegal-char"
+	RepairAmpersand     = "unescaped-ampersand"
+	RepairHTMLEntity    = "html-entity"
+	RepairTruncated     = "truncated-document"
+	maxEntityNameLength = 32
+)
+
+// RepairOptions selects which repairs RepairXML performs.
+type RepairOptions struct {
+	StripIllegalChars   bool
+	EscapeAmpersands    bool
+	ReplaceHTMLEntities bool
+	CloseTruncated      bool
+}
+
+// Repair describes a single change RepairXML made to a document.
+// Offset is the byte offset of the change in the original input.
+type Repair struct {
+	Kind   string
+	Offset int
+	Detail string
+}
+
+// RepairXML makes a best-effort pass over an XML document and
+// fixes the problems most commonly found in broken feeds: illegal
+// control characters, unescaped ampersands, HTML named entities
+// which are undefined in XML, and documents which end before all
+// of their elements were closed.
+//
+// The scanner only treats ASCII bytes as markup, so it is safe to
+// use on any ASCII compatible encoding.  CDATA sections, comments
+// and processing instructions are copied verbatim apart from the
+// removal of illegal characters.
+func RepairXML(data []byte, opts RepairOptions) ([]byte, []Repair) {
+	r := &xmlRepairer{data: data, opts: opts}
+	r.out.Grow(len(data))
+	r.run()
+	return r.out.Bytes(), r.repairs
+}
+
+type xmlRepairer struct {
+	data    []byte
+	opts    RepairOptions
+	pos     int
+	out     bytes.Buffer
+	stack   []string
+	repairs []Repair
+}
+
+func (r *xmlRepairer) report(kind string, offset int, format string, args ...interface{}) {
+	r.repairs = append(r.repairs, Repair{
+		Kind:   kind,
+		Offset: offset,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *xmlRepairer) run() {
+	for r.pos < len(r.data) {
+		switch {
+		case r.hasPrefix("<!--"):
+			r.copyUntil("-->", "comment")
+		case r.hasPrefix("<![CDATA["):
+			r.copyUntil("]]>", "CDATA section")
+		case r.hasPrefix("<?"):
+			r.copyUntil("?>", "processing instruction")
+		case r.hasPrefix("<!"):
+			r.copyDirective()
+		case r.data[r.pos] == '<':
+			r.copyTag()
+		case r.data[r.pos] == '&':
+			r.copyReference()
+		default:
+			r.copyChar()
+		}
+	}
+
+	if r.opts.CloseTruncated && len(r.stack) > 0 {
+		for i := len(r.stack) - 1; i >= 0; i-- {
+			r.out.WriteString("</" + r.stack[i] + ">")
+			r.report(RepairTruncated, len(r.data), "closed unterminated element <%s>", r.stack[i])
+		}
+		r.stack = nil
+	}
+}
+
+func (r *xmlRepairer) hasPrefix(prefix string) bool {
+	return bytes.HasPrefix(r.data[r.pos:], []byte(prefix))
+}
+
+// copyChar copies the character at the current position,
+// dropping it if it is not a legal XML character.
+func (r *xmlRepairer) copyChar() {
+	b := r.data[r.pos]
+	if b < utf8.RuneSelf {
+		if r.opts.StripIllegalChars && !isXMLChar(rune(b)) {
+			r.dropIllegal(1, rune(b))
+			return
+		}
+		r.out.WriteByte(b)
+		r.pos++
+		return
+	}
+
+	c, size := utf8.DecodeRune(r.data[r.pos:])
+	if r.opts.StripIllegalChars && c != utf8.RuneError && !isXMLChar(c) {
+		r.dropIllegal(size, c)
+		return
+	}
+	r.out.Write(r.data[r.pos : r.pos+size])
+	r.pos += size
+}
+
+func (r *xmlRepairer) dropIllegal(size int, c rune) {
+	r.report(RepairIllegalChar, r.pos, "removed illegal character %U", c)
+	r.pos += size
+}
+
+// copyUntil copies a comment, CDATA section or processing
+// instruction up to and including its terminator.
+func (r *xmlRepairer) copyUntil(end string, what string) {
+	start := r.pos
+	idx := bytes.Index(r.data[r.pos+1:], []byte(end))
+	stop := len(r.data)
+	if idx != -1 {
+		stop = r.pos + 1 + idx + len(end)
+	}
+
+	for r.pos < stop {
+		r.copyChar()
+	}
+
+	if idx == -1 && r.opts.CloseTruncated {
+		r.out.WriteString(end)
+		r.report(RepairTruncated, start, "closed unterminated %s", what)
+	}
+}
+
+// copyDirective copies a <!DOCTYPE ...> style directive,
+// including any internal subset enclosed in brackets.
+func (r *xmlRepairer) copyDirective() {
+	depth := 0
+	for r.pos < len(r.data) {
+		b := r.data[r.pos]
+		r.copyChar()
+		if b == '[' {
+			depth++
+		} else if b == ']' {
+			depth--
+		} else if b == '>' && depth <= 0 {
+			return
+		}
+	}
+}
+
+// copyTag copies a start or end tag and keeps track of
+// the currently open elements.
+func (r *xmlRepairer) copyTag() {
+	start := r.pos
+	end := r.tagEnd()
+	if end == -1 {
+		// The document ends in the middle of a tag.  The partial
+		// tag can't be salvaged, so drop it when closing the
+		// document or leave it for the parser to complain about.
+		if r.opts.CloseTruncated {
+			r.report(RepairTruncated, start, "removed incomplete tag at end of document")
+			r.pos = len(r.data)
+			return
+		}
+		for r.pos < len(r.data) {
+			r.copyChar()
+		}
+		return
+	}
+
+	tag := r.data[start:end]
+	name := tagName(tag)
+	if name == "" {
+		// Not a tag, just a stray '<' in text
+		r.copyChar()
+		return
+	}
+
+	r.out.WriteByte('<')
+	r.pos++
+	for r.pos < end {
+		if r.data[r.pos] == '&' {
+			r.copyReference()
+		} else {
+			r.copyChar()
+		}
+	}
+
+	if tag[1] == '/' {
+		for i := len(r.stack) - 1; i >= 0; i-- {
+			if r.stack[i] == name {
+				r.stack = r.stack[:i]
+				break
+			}
+		}
+	} else if tag[len(tag)-2] != '/' {
+		r.stack = append(r.stack, name)
+	}
+}
+
+// tagEnd returns the index just past the '>' that closes the
+// tag at the current position, skipping over quoted attribute
+// values, or -1 if the tag is never closed.
+func (r *xmlRepairer) tagEnd() int {
+	var quote byte
+	for i := r.pos + 1; i < len(r.data); i++ {
+		b := r.data[i]
+		if quote != 0 {
+			if b == quote {
+				quote = 0
+			}
+		} else if b == '"' || b == '\'' {
+			quote = b
+		} else if b == '>' {
+			return i + 1
+		} else if b == '<' {
+			// Whatever this was, it wasn't a well formed tag
+			return i
+		}
+	}
+	return -1
+}
+
+// tagName returns the element name of a raw start or end tag,
+// or an empty string if tag does not begin with a valid name.
+func tagName(tag []byte) string {
+	i := 1
+	if i < len(tag) && tag[i] == '/' {
+		i++
+	}
+	start := i
+	for i < len(tag) && isNameByte(tag[i], i == start) {
+		i++
+	}
+	return string(tag[start:i])
+}
+
+func isNameByte(b byte, first bool) bool {
+	if b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_' || b == ':' || b >= utf8.RuneSelf {
+		return true
+	}
+	return !first && (b >= '0' && b <= '9' || b == '-' || b == '.')
+}
+
+// copyReference copies the character or entity reference at the
+// current position, replacing HTML entities and escaping bare
+// ampersands when enabled.
+func (r *xmlRepairer) copyReference() {
+	ref, ok := r.reference()
+	if !ok {
+		if r.opts.EscapeAmpersands {
+			r.report(RepairAmpersand, r.pos, "escaped bare ampersand")
+			r.out.WriteString("&amp;")
+		} else {
+			r.out.WriteByte('&')
+		}
+		r.pos++
+		return
+	}
+
+	name := ref[1 : len(ref)-1]
+	if name[0] == '#' || isPredefinedEntity(name) {
+		r.out.WriteString(ref)
+		r.pos += len(ref)
+		return
+	}
+
+	unescaped, ok := unescapeEntity(ref)
+	if !ok {
+		// Unknown entity.  XML parsers reject these, so
+		// treat the ampersand as literal text.
+		if r.opts.EscapeAmpersands {
+			r.report(RepairAmpersand, r.pos, "escaped ampersand of undefined entity %s", ref)
+			r.out.WriteString("&amp;")
+			r.pos++
+			return
+		}
+	} else if r.opts.ReplaceHTMLEntities {
+		// Character references keep markup characters such as
+		// &LT; escaped, and are right whatever the encoding
+		// of the document.
+		r.report(RepairHTMLEntity, r.pos, "replaced entity %s", ref)
+		for _, c := range unescaped {
+			fmt.Fprintf(&r.out, "&#%d;", c)
+		}
+		r.pos += len(ref)
+		return
+	}
+
+	r.out.WriteString(ref)
+	r.pos += len(ref)
+}
+
+// unescapeEntity returns the characters of an HTML entity
+// reference.  It fails for unknown entities, including those
+// which only begin with the name of an entity, such as &notit;
+// which html.UnescapeString decodes as &not followed by "it;".
+func unescapeEntity(ref string) (string, bool) {
+	unescaped := html.UnescapeString(ref)
+	// Entities are at most two characters, and the rest
+	// of a partly decoded name at least two more.
+	if unescaped == ref || utf8.RuneCountInString(unescaped) > 2 {
+		return "", false
+	}
+	return unescaped, true
+}
+
+// reference returns the syntactically valid entity or character
+// reference starting at the current position.
+func (r *xmlRepairer) reference() (string, bool) {
+	rest := r.data[r.pos+1:]
+	if len(rest) > maxEntityNameLength+1 {
+		rest = rest[:maxEntityNameLength+1]
+	}
+
+	end := bytes.IndexByte(rest, ';')
+	if end < 1 {
+		return "", false
+	}
+	name := rest[:end]
+
+	if name[0] == '#' {
+		digits := name[1:]
+		hex := len(digits) > 0 && (digits[0] == 'x' || digits[0] == 'X')
+		if hex {
+			digits = digits[1:]
+		}
+		if len(digits) == 0 {
+			return "", false
+		}
+		for _, b := range digits {
+			if !(b >= '0' && b <= '9' || hex && (b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F')) {
+				return "", false
+			}
+		}
+	} else {
+		for i, b := range name {
+			if !isNameByte(b, i == 0) || b >= utf8.RuneSelf {
+				return "", false
+			}
+		}
+	}
+	return "&" + string(name) + ";", true
+}
+
+func isPredefinedEntity(name string) bool {
+	switch name {
+	case "amp", "lt", "gt", "quot", "apos":
+		return true
+	}
+	return false
+}
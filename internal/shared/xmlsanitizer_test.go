@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXMLSanitizerReader(t *testing.T) {
+	r := NewXMLSanitizerReader(strings.NewReader("a\x0cb\x00cé"))
+	result, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "abcé", string(result))
+}
+
+func TestRepairXML(t *testing.T) {
+	all := RepairOptions{
+		StripIllegalChars:   true,
+		EscapeAmpersands:    true,
+		ReplaceHTMLEntities: true,
+		CloseTruncated:      true,
+	}
+
+	tests := []struct {
+		in    string
+		out   string
+		kinds []string
+	}{
+		{"<a>ok</a>", "<a>ok</a>", nil},
+		{"<a>form\x0cfeed</a>", "<a>formfeed</a>", []string{RepairIllegalChar}},
+		{"<a>￾</a>", "<a></a>", []string{RepairIllegalChar}},
+		{"<a>fish & chips</a>", "<a>fish &amp; chips</a>", []string{RepairAmpersand}},
+		{`<a href="?x=1&y=2"/>`, `<a href="?x=1&amp;y=2"/>`, []string{RepairAmpersand}},
+		{"<a>&amp; &lt; &#233; &#xE9;</a>", "<a>&amp; &lt; &#233; &#xE9;</a>", nil},
+		{"<a>caf&eacute;&nbsp;</a>", "<a>caf&#233;&#160;</a>", []string{RepairHTMLEntity, RepairHTMLEntity}},
+		{"<a>&LT;b&AMP;&QUOT;</a>", "<a>&#60;b&#38;&#34;</a>", []string{RepairHTMLEntity, RepairHTMLEntity, RepairHTMLEntity}},
+		{"<a>&bogus;</a>", "<a>&amp;bogus;</a>", []string{RepairAmpersand}},
+		{"<a>&notit;</a>", "<a>&amp;notit;</a>", []string{RepairAmpersand}},
+		{"<a><![CDATA[& \x0c&nbsp;]]></a>", "<a><![CDATA[& &nbsp;]]></a>", []string{RepairIllegalChar}},
+		{"<!-- & --><a/>", "<!-- & --><a/>", nil},
+		{"<rss><channel><title>t", "<rss><channel><title>t</title></channel></rss>", []string{RepairTruncated, RepairTruncated, RepairTruncated}},
+		{"<a><b>x</b><c", "<a><b>x</b></a>", []string{RepairTruncated, RepairTruncated}},
+		{"<a><![CDATA[x", "<a><![CDATA[x]]></a>", []string{RepairTruncated, RepairTruncated}},
+		{"<a><br><p>x</a>", "<a><br><p>x</a>", nil},
+		{"<a>1 < 2</a>", "<a>1 < 2</a>", nil},
+	}
+
+	for _, test := range tests {
+		out, repairs := RepairXML([]byte(test.in), all)
+		assert.Equal(t, test.out, string(out), "repairing %q", test.in)
+
+		var kinds []string
+		for _, r := range repairs {
+			kinds = append(kinds, r.Kind)
+		}
+		assert.Equal(t, test.kinds, kinds, "repairing %q", test.in)
+	}
+}
+
+func TestRepairXML_Disabled(t *testing.T) {
+	in := "<a>fish & chips&nbsp;\x0c<b>"
+	out, repairs := RepairXML([]byte(in), RepairOptions{})
+	assert.Equal(t, in, string(out))
+	assert.Nil(t, repairs)
+}
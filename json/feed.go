@@ -1,6 +1,13 @@
 package json
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
 
 // Feed describes the structure for JSON Feed v1.0
 // https://www.jsonfeed.org/version/1/
@@ -17,8 +24,11 @@ type Feed struct {
 	Author      *Author `json:"author,omitempty"`        // author (optional, object) specifies the feed author. The author object has several members. These are all optional — but if you provide an author object, then at least one is required:
 	Expired     bool    `json:"expired,omitempty"`       // expired (optional, boolean) says whether or not the feed is finished — that is, whether or not it will ever update again.
 	Items       []*Item `json:"items"`                   // items is an array, and is required
-	// TODO Hubs // hubs (very optional, array of objects) describes endpoints that can be used to subscribe to real-time notifications from the publisher of this feed. Each object has a type and url, both of which are required. See the section “Subscribing to Real-time Notifications” below for details.
-	// TODO Extensions
+	Hubs        []*Hub  `json:"hubs,omitempty"`          // hubs (very optional, array of objects) describes endpoints that can be used to subscribe to real-time notifications from the publisher of this feed. Each object has a type and url, both of which are required.
+
+	// Extensions holds the custom objects of the feed, keyed
+	// by their names including the leading underscore.
+	Extensions Extensions `json:"-"`
 
 	// Version 1.1
 	Authors  []*Author `json:"authors,omitempty"`
@@ -30,6 +40,20 @@ func (f Feed) String() string {
 	return string(json)
 }
 
+// UnmarshalJSON decodes a feed along with its extensions.
+func (f *Feed) UnmarshalJSON(data []byte) error {
+	type feed Feed
+	ext, err := unmarshalObject(data, (*feed)(f))
+	f.Extensions = ext
+	return err
+}
+
+// MarshalJSON encodes a feed along with its extensions.
+func (f Feed) MarshalJSON() ([]byte, error) {
+	type feed Feed
+	return marshalExtensions(feed(f), f.Extensions)
+}
+
 // Item defines an item in the feed
 type Item struct {
 	ID            string  `json:"id,omitempty"`             // id (required, string) is unique for that item for that feed over time. If an id is presented as a number or other type, a JSON Feed reader must coerce it to a string. Ideally, the id is the full URL of the resource described by the item, since URLs make great unique identifiers.
@@ -47,13 +71,30 @@ type Item struct {
 
 	Tags        []string       `json:"tags,omitempty"`        // tags (optional, array of strings) can have any plain text values you want. Tags tend to be just one word, but they may be anything.
 	Attachments *[]Attachments `json:"attachments,omitempty"` // attachments (optional, array) lists related resources. Podcasts, for instance, would include an attachment that’s an audio or video file. An individual item may have one or more attachments.
-	// TODO Extensions
+
+	// Extensions holds the custom objects of the item, keyed
+	// by their names including the leading underscore.
+	Extensions Extensions `json:"-"`
 
 	// Version 1.1
 	Authors  []*Author `json:"authors,omitempty"`
 	Language string    `json:"language,omitempty"`
 }
 
+// UnmarshalJSON decodes an item along with its extensions.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type item Item
+	ext, err := unmarshalObject(data, (*item)(i))
+	i.Extensions = ext
+	return err
+}
+
+// MarshalJSON encodes an item along with its extensions.
+func (i Item) MarshalJSON() ([]byte, error) {
+	type item Item
+	return marshalExtensions(item(i), i.Extensions)
+}
+
 // Author defines the feed author structure. The author object has several members. These are all optional — but if you provide an author object, then at least one is required:
 type Author struct {
 	Name   string `json:"name,omitempty"`   // name (optional, string) is the author’s name.
@@ -69,3 +110,105 @@ type Attachments struct {
 	SizeInBytes       int64  `json:"size_in_bytes,omitempty"`       // size_in_bytes (optional, number) specifies how large the file is.
 	DurationInSeconds int64  `json:"duration_in_seconds,omitempty"` // duration_in_seconds (optional, number) specifies how long it takes to listen to or watch, when played at normal speed.
 }
+
+// Hub describes an endpoint that can be used to subscribe to real-time notifications from the publisher of the feed
+type Hub struct {
+	Type string `json:"type,omitempty"` // type (required, string) is the protocol of the hub, such as “WebSub”.
+	URL  string `json:"url,omitempty"`  // url (required, string) is the URL of the hub.
+}
+
+// Extensions are the custom objects of a feed or item, whose names
+// begin with an underscore (e.g. _blue_shed).  Values are decoded
+// as by encoding/json into an interface{}.
+type Extensions map[string]interface{}
+
+// unmarshalObject decodes a JSON object into the struct v points
+// to and returns the members whose names begin with an underscore,
+// reading the object once.  Like encoding/json, it matches names to
+// the fields of the struct without regard to case.
+func unmarshalObject(data []byte, v interface{}) (Extensions, error) {
+	fields := jsonFields(reflect.ValueOf(v).Elem())
+
+	iter := j.BorrowIterator(data)
+	defer j.ReturnIterator(iter)
+
+	var ext Extensions
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, name string) bool {
+		if strings.HasPrefix(name, "_") {
+			if ext == nil {
+				ext = Extensions{}
+			}
+			ext[name] = iter.Read()
+		} else if field := fieldNamed(fields, name); field != nil {
+			iter.ReadVal(field)
+		} else {
+			iter.Skip()
+		}
+		return iter.Error == nil
+	})
+	err := iter.Error
+	if err == nil {
+		// Only whitespace may follow the object
+		if iter.WhatIsNext(); iter.Error != io.EOF {
+			iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
+			err = iter.Error
+		}
+	}
+	if err != nil {
+		// Decode again for the error of the struct decoder,
+		// which says more about where the document is wrong.
+		if err := j.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	return ext, nil
+}
+
+// jsonFields returns pointers to the fields of a struct,
+// keyed by their names in JSON.
+func jsonFields(v reflect.Value) map[string]interface{} {
+	fields := map[string]interface{}{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		fields[name] = v.Field(i).Addr().Interface()
+	}
+	return fields
+}
+
+func fieldNamed(fields map[string]interface{}, name string) interface{} {
+	if field, ok := fields[name]; ok {
+		return field
+	}
+	for n, field := range fields {
+		if strings.EqualFold(n, name) {
+			return field
+		}
+	}
+	return nil
+}
+
+// marshalExtensions encodes v, which must encode to a
+// JSON object, with the extensions added as members.
+func marshalExtensions(v interface{}, ext Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return data, err
+	}
+	extData, err := json.Marshal(map[string]interface{}(ext))
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "{}" {
+		return extData, nil
+	}
+	data = append(data[:len(data)-1], ',')
+	return append(data, extData[1:]...), nil
+}
@@ -2,9 +2,11 @@ package json
 
 import (
 	"bytes"
+	"context"
 	"io"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/mmcdole/gofeed/internal/shared"
 )
 
 var (
@@ -16,12 +18,26 @@ type Parser struct{}
 
 // Parse parses an json feed into an json.Feed
 func (ap *Parser) Parse(feed io.Reader) (*Feed, error) {
-	jsonFeed := &Feed{}
+	return ap.ParseWithContext(feed, context.Background())
+}
 
+// ParseWithContext is like Parse but stops with the error of
+// the context once it's done.  The context is checked as the
+// document is read.
+func (ap *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Feed, error) {
 	buffer := new(bytes.Buffer)
-	buffer.ReadFrom(feed)
+	if _, err := buffer.ReadFrom(shared.NewContextReader(ctx, feed)); err != nil {
+		return nil, err
+	}
+	return ap.ParseBytes(buffer.Bytes())
+}
 
-	err := j.Unmarshal(buffer.Bytes(), jsonFeed)
+// ParseBytes parses a json feed held in memory into a json.Feed.
+func (ap *Parser) ParseBytes(feed []byte) (*Feed, error) {
+	// j.Unmarshal would skip over the whole object before
+	// calling UnmarshalJSON, losing where syntax errors are.
+	jsonFeed := &Feed{}
+	err := jsonFeed.UnmarshalJSON(feed)
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,9 @@ package json_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -118,3 +120,55 @@ func TestParser_ParseInvalidAndStruct(t *testing.T) {
 }
 
 // TODO: Examples
+
+func TestParser_ParseWithContext(t *testing.T) {
+	fp := &jsonParser.Parser{}
+	feed, err := fp.ParseWithContext(strings.NewReader(`{"version": "https://jsonfeed.org/version/1.1", "title": "Title"}`), context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", feed.Title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	feed, err = fp.ParseWithContext(strings.NewReader(`{"version": "https://jsonfeed.org/version/1.1", "title": "Title"}`), ctx)
+	assert.Nil(t, feed)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestParser_ParseHubsAndExtensions(t *testing.T) {
+	feedData := `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"hubs": [{"type": "WebSub", "url": "https://hub.example.com/"}],
+		"_blue_shed": {"explicit": false, "owner": {"name": "Big Brother"}},
+		"items": [{"id": "1", "_blue_shed": {"duration": 1800}}]
+	}`
+
+	fp := &jsonParser.Parser{}
+	actual, err := fp.Parse(strings.NewReader(feedData))
+	assert.Nil(t, err)
+	assert.Equal(t, []*jsonParser.Hub{{Type: "WebSub", URL: "https://hub.example.com/"}}, actual.Hubs)
+	assert.Equal(t, jsonParser.Extensions{
+		"_blue_shed": map[string]interface{}{
+			"explicit": false,
+			"owner":    map[string]interface{}{"name": "Big Brother"},
+		},
+	}, actual.Extensions)
+	assert.Equal(t, jsonParser.Extensions{
+		"_blue_shed": map[string]interface{}{"duration": float64(1800)},
+	}, actual.Items[0].Extensions)
+
+	// Extensions survive encoding
+	data, err := json.Marshal(actual)
+	assert.Nil(t, err)
+	roundTrip := &jsonParser.Feed{}
+	assert.Nil(t, json.Unmarshal(data, roundTrip))
+	assert.Equal(t, actual, roundTrip)
+
+	// Names match fields whatever their case, as
+	// with encoding/json, and nothing may follow
+	actual, err = fp.Parse(strings.NewReader(`{"Title": "t", "_x": 1}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "t", actual.Title)
+	assert.Equal(t, jsonParser.Extensions{"_x": float64(1)}, actual.Extensions)
+	_, err = fp.Parse(strings.NewReader(`{"title": "t"} x`))
+	assert.NotNil(t, err)
+}
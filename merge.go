@@ -0,0 +1,101 @@
+package gofeed
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MergeOptions control how Merge combines feeds.
+type MergeOptions struct {
+	// Identity identifies duplicate items.  A nil
+	// Identity uses Item.Identity.
+	Identity IdentityFunc
+	// SortKey is the date the merged items are sorted
+	// by, newest first.  DefaultMergeOptions sets it to
+	// SortByDate; the zero value is SortByPublished.
+	SortKey SortKey
+	// Limit is the maximum number of items kept after
+	// sorting.  Zero keeps all of them.
+	Limit int
+}
+
+// DefaultMergeOptions returns options which sort merged
+// items by their effective date and keep all of them.
+func DefaultMergeOptions() *MergeOptions {
+	return &MergeOptions{SortKey: SortByDate}
+}
+
+// Merge combines the items of several feeds into a new feed, as
+// for a "planet" style aggregate.  Items with the same identity
+// are kept once, from the first feed holding them.  As GUIDs are
+// only unique within a feed, items of different feeds identified
+// by their GUIDs are only the same if their links are too.  Each item
+// is tagged with the feed it came from unless it already has a
+// Source.  The merged items are copies, so the given feeds are
+// left unchanged, though the copies share their nested values.
+// The merged feed has no title or link of its own; its Updated
+// date is that of its newest item.  Nil options use
+// DefaultMergeOptions.
+func Merge(feeds []*Feed, opts *MergeOptions) *Feed {
+	if opts == nil {
+		opts = DefaultMergeOptions()
+	}
+	identity := opts.Identity
+	if identity == nil {
+		identity = (*Item).Identity
+	}
+
+	merged := &Feed{Items: []*Item{}}
+	seen := map[string]bool{}
+	for i, feed := range feeds {
+		if feed == nil {
+			continue
+		}
+		source := &Source{Title: feed.Title, Link: feed.Link, FeedURL: feed.FeedLink}
+		inFeed := map[string]bool{}
+		for _, item := range feed.Items {
+			if item == nil {
+				continue
+			}
+			id := identity(item)
+			if inFeed[id] {
+				continue
+			}
+			inFeed[id] = true
+
+			key := id
+			if strings.HasPrefix(id, IdentityGUID) {
+				if link := NormalizeURL(item.Link); link != "" {
+					key += " " + link
+				} else {
+					key = strconv.Itoa(i) + " " + id
+				}
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			c := *item
+			if c.Source == nil {
+				c.Source = source
+			}
+			merged.Items = append(merged.Items, &c)
+		}
+	}
+
+	SortItems(merged.Items, opts.SortKey, Descending)
+	if opts.Limit > 0 && len(merged.Items) > opts.Limit {
+		merged.Items = merged.Items[:opts.Limit]
+	}
+
+	if len(merged.Items) > 0 {
+		if newest := itemDate(merged.Items[0], opts.SortKey); newest != nil {
+			updated := *newest
+			merged.UpdatedParsed = &updated
+			merged.Updated = updated.Format(time.RFC3339)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,86 @@
+package gofeed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	at := func(hour int) *time.Time {
+		return &[]time.Time{time.Date(2006, time.January, 2, hour, 0, 0, 0, time.UTC)}[0]
+	}
+	upstream := &gofeed.Source{Title: "Upstream"}
+
+	a := &gofeed.Feed{
+		Title:    "A",
+		Link:     "http://a.example.org/",
+		FeedLink: "http://a.example.org/feed",
+		Items: []*gofeed.Item{
+			{GUID: "a1", DateParsed: at(1)},
+			{GUID: "shared", Link: "http://example.org/shared", DateParsed: at(3)},
+			{GUID: "1", DateParsed: at(0)},
+			{GUID: "a1", DateParsed: at(5)},
+			nil,
+		},
+	}
+	b := &gofeed.Feed{
+		Title: "B",
+		Items: []*gofeed.Item{
+			{GUID: "shared", Link: "http://EXAMPLE.org/shared?utm_source=b", DateParsed: at(4)},
+			{GUID: "b1", DateParsed: at(2), Source: upstream},
+			{GUID: "1", DateParsed: at(0)},
+			{GUID: "b2"},
+		},
+	}
+
+	merged := gofeed.Merge([]*gofeed.Feed{a, nil, b}, nil)
+
+	ids := []string{}
+	for _, item := range merged.Items {
+		ids = append(ids, item.GUID)
+	}
+	// GUIDs of different feeds only match with their links
+	assert.Equal(t, []string{"shared", "b1", "a1", "1", "1", "b2"}, ids)
+
+	assert.Equal(t, &gofeed.Source{Title: "A", Link: "http://a.example.org/", FeedURL: "http://a.example.org/feed"}, merged.Items[0].Source)
+	assert.Equal(t, upstream, merged.Items[1].Source)
+	assert.Equal(t, "B", merged.Items[5].Source.Title)
+	assert.Equal(t, "2006-01-02T03:00:00Z", merged.Updated)
+	assert.Equal(t, *at(3), *merged.UpdatedParsed)
+
+	// The source feeds are unchanged
+	assert.Nil(t, a.Items[0].Source)
+	assert.NotSame(t, a.Items[0], merged.Items[2])
+}
+
+func TestMerge_Options(t *testing.T) {
+	feed := &gofeed.Feed{Items: []*gofeed.Item{
+		{Title: "x", PublishedParsed: &[]time.Time{time.Unix(1, 0)}[0]},
+		{Title: "x", PublishedParsed: &[]time.Time{time.Unix(3, 0)}[0]},
+		{Title: "y", PublishedParsed: &[]time.Time{time.Unix(2, 0)}[0]},
+		{Title: "z"},
+	}}
+
+	// The default options sort by DateParsed
+	dated := &gofeed.Feed{Items: []*gofeed.Item{
+		{Title: "old", DateParsed: &[]time.Time{time.Unix(1, 0)}[0], PublishedParsed: &[]time.Time{time.Unix(3, 0)}[0]},
+		{Title: "new", DateParsed: &[]time.Time{time.Unix(2, 0)}[0]},
+	}}
+	byDate := gofeed.Merge([]*gofeed.Feed{dated}, gofeed.DefaultMergeOptions())
+	assert.Equal(t, "new", byDate.Items[0].Title)
+
+	merged := gofeed.Merge([]*gofeed.Feed{feed}, &gofeed.MergeOptions{
+		Identity: func(item *gofeed.Item) string { return item.Title },
+		SortKey:  gofeed.SortByPublished,
+		Limit:    2,
+	})
+
+	if assert.Len(t, merged.Items, 2) {
+		assert.Equal(t, "y", merged.Items[0].Title)
+		assert.Equal(t, "x", merged.Items[1].Title)
+		assert.Equal(t, int64(1), merged.Items[1].PublishedParsed.Unix())
+	}
+}
@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/mmcdole/gofeed/atom"
+	"github.com/mmcdole/gofeed/dates"
+	"github.com/mmcdole/gofeed/internal/shared"
 	"github.com/mmcdole/gofeed/json"
 	"github.com/mmcdole/gofeed/rss"
 )
@@ -35,12 +40,59 @@ type Parser struct {
 	AtomTranslator Translator
 	RSSTranslator  Translator
 	JSONTranslator Translator
-	UserAgent      string
-	AuthConfig     *Auth
-	Client         *http.Client
-	rp             *rss.Parser
-	ap             *atom.Parser
-	jp             *json.Parser
+	// HFeedTranslator translates the h-feeds of HTML
+	// pages (see FeedTypeHFeed).
+	HFeedTranslator Translator
+	// ActivityStreamsTranslator translates ActivityStreams
+	// collections (see FeedTypeActivityStreams).
+	ActivityStreamsTranslator Translator
+	UserAgent                 string
+	AuthConfig                *Auth
+	Client                    *http.Client
+	// XMLRepair selects the repairs applied to RSS and Atom
+	// documents before they are parsed. Repairs that were made
+	// are reported in Feed.Repairs. A nil value disables the
+	// repair stage.
+	XMLRepair *XMLRepairOptions
+	// RecoverMalformed enables a fallback for RSS and Atom
+	// documents which fail to parse. The document is re-read
+	// with a forgiving HTML5 tokenizer and rebuilt on a
+	// best-effort basis. Feeds recovered this way have
+	// Feed.Recovered set.
+	RecoverMalformed bool
+	// DateParser parses the dates of RSS and Atom feeds and
+	// those read by the default translators. A nil DateParser
+	// uses a dates.StandardParser, which understands localized
+	// month and day names.
+	DateParser dates.Parser
+	// FirstSeen is passed to the default translators to date
+	// items that have no date of their own and belong to a
+	// feed without a date (see Item.DateSource).
+	FirstSeen func(item *Item) time.Time
+	// Sanitizer is passed to the default translators to
+	// remove unsafe HTML from the Content and Description
+	// of items. A nil Sanitizer leaves them unchanged.
+	Sanitizer *SanitizePolicy
+	// ImageSources is passed to the default translators to
+	// choose where the images of items are taken from (see
+	// DefaultImageSources).
+	ImageSources []ImageSource
+	// ResolveURLs makes the relative URLs of feeds and items,
+	// including those in their HTML, absolute. RSS and JSON
+	// links are resolved against the feed's link and, like
+	// Atom links, against the URL the feed was fetched from.
+	ResolveURLs bool
+	// ItemHooks and FeedHooks run, in order, after a feed has
+	// been translated by any translator, default or not, and
+	// before its relative URLs are resolved.  They may change
+	// the feed, for example to fill in Custom or to work around
+	// the quirks of a publisher.  The item hooks run on every
+	// item first.  An error from a hook fails the parse.
+	ItemHooks []ItemHook
+	FeedHooks []FeedHook
+	rp        *rss.Parser
+	ap        *atom.Parser
+	jp        *json.Parser
 }
 
 // Auth is a structure allowing to
@@ -58,6 +110,9 @@ func NewParser() *Parser {
 		ap:        &atom.Parser{},
 		jp:        &json.Parser{},
 		UserAgent: "Gofeed/1.0",
+		XMLRepair: DefaultXMLRepairOptions(),
+
+		ResolveURLs: true,
 	}
 	return &fp
 }
@@ -66,31 +121,128 @@ func NewParser() *Parser {
 // the universal gofeed.Feed.  It takes an
 // io.Reader which should return the xml/json content.
 func (f *Parser) Parse(feed io.Reader) (*Feed, error) {
-	// Wrap the feed io.Reader in a io.TeeReader
-	// so we can capture all the bytes read by the
-	// DetectFeedType function and construct a new
-	// reader with those bytes intact for when we
-	// attempt to parse the feeds.
+	return f.ParseWithContext(feed, context.Background())
+}
+
+// ParseWithContext is like Parse but stops with the error
+// of the context once it's done, whether the feed is still
+// being read, parsed or translated.
+func (f *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Feed, error) {
+	return f.parse(feed, nil, ctx)
+}
+
+// ParseBytes parses a feed held in memory into the
+// universal feed type.  Unlike Parse it doesn't
+// need to copy the feed first.
+func (f *Parser) ParseBytes(feed []byte) (*Feed, error) {
+	return f.ParseBytesWithContext(feed, context.Background())
+}
+
+// ParseBytesWithContext is like ParseBytes but stops with
+// the error of the context once it's done.
+func (f *Parser) ParseBytesWithContext(feed []byte, ctx context.Context) (*Feed, error) {
+	return f.parseBytes(feed, nil, ctx)
+}
+
+// ParseFile reads and parses the feed in the named
+// file into the universal feed type.
+func (f *Parser) ParseFile(name string) (*Feed, error) {
+	return f.ParseFileWithContext(name, context.Background())
+}
+
+// ParseFileWithContext is like ParseFile but stops with
+// the error of the context once it's done.
+func (f *Parser) ParseFileWithContext(name string, ctx context.Context) (*Feed, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Read the file straight into a buffer of its size
 	var buf bytes.Buffer
-	tee := io.TeeReader(feed, &buf)
-	feedType := DetectFeedType(tee)
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		buf.Grow(int(info.Size()) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(shared.NewContextReader(ctx, file)); err != nil {
+		return nil, err
+	}
+	return f.parseBytes(buf.Bytes(), nil, ctx)
+}
 
-	// Glue the read bytes from the detect function
-	// back into a new reader
-	r := io.MultiReader(&buf, feed)
+// parse parses a feed which was fetched from feedURL,
+// or from an unknown location if feedURL is nil.
+func (f *Parser) parse(feed io.Reader, feedURL *url.URL, ctx context.Context) (*Feed, error) {
+	// Read the whole document up front so that it can be
+	// repaired before the feed type is detected and the
+	// same bytes can then be handed to the feed parsers.
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(shared.NewContextReader(ctx, feed)); err != nil {
+		return nil, err
+	}
+	return f.parseBytes(buf.Bytes(), feedURL, ctx)
+}
+
+// parseBytes parses a feed document held in memory.
+func (f *Parser) parseBytes(data []byte, feedURL *url.URL, ctx context.Context) (*Feed, error) {
+	data, repairs := f.repairXML(data)
+	result, err := f.parseDocument(data, ctx)
+	if err != nil && ctx.Err() == nil && f.RecoverMalformed && looksLikeXML(data) {
+		// Report the original error if the document
+		// can't be recovered either.
+		if recovered, rerr := f.recoverDocument(data, ctx); rerr == nil {
+			result, err = recovered, nil
+		}
+	}
 
+	if err != nil {
+		return nil, err
+	}
+	result.Repairs = repairs
+	if f.ResolveURLs {
+		resolveFeedURLs(result, feedURL)
+	}
+	return result, nil
+}
+
+// parseDocument detects the type of a feed document
+// and parses it with the matching feed parser.
+func (f *Parser) parseDocument(data []byte, ctx context.Context) (*Feed, error) {
+	r := bytes.NewReader(data)
+
+	feedType := DetectFeedType(bytes.NewReader(data))
 	switch feedType {
 	case FeedTypeAtom:
-		return f.parseAtomFeed(r)
+		return f.parseAtomFeed(r, ctx)
 	case FeedTypeRSS:
-		return f.parseRSSFeed(r)
+		return f.parseRSSFeed(r, ctx)
 	case FeedTypeJSON:
-		return f.parseJSONFeed(r)
+		return f.parseJSONFeed(data, ctx)
+	}
+
+	if format := lookupFormat(feedType); format != nil {
+		return f.parseFormat(format, data, ctx)
 	}
 
 	return nil, ErrFeedTypeNotDetected
 }
 
+// recoverDocument rebuilds a malformed XML document
+// as well formed XML and parses it again.
+func (f *Parser) recoverDocument(data []byte, ctx context.Context) (*Feed, error) {
+	rebuilt, err := shared.RebuildXML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := f.parseDocument(rebuilt, ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Recovered = true
+	return result, nil
+}
+
 // ParseURL fetches the contents of a given url and
 // attempts to parse the response into the universal feed type.
 func (f *Parser) ParseURL(feedURL string) (feed *Feed, err error) {
@@ -131,6 +283,59 @@ func (f *Parser) ParseURLWithContext(feedURL string, ctx context.Context) (feed
 		}()
 	}
 
+	return f.ParseResponse(resp)
+}
+
+// ParseURLPages is like ParseURL but also fetches the pages which
+// follow the feed, by its NextLink, and appends their items to
+// those of the feed.  At most maxPages documents are fetched,
+// including the feed itself.  A maxPages of 0 or less fetches
+// every page.  The NextLink of the result is the first page that
+// wasn't fetched.
+func (f *Parser) ParseURLPages(feedURL string, maxPages int) (*Feed, error) {
+	return f.ParseURLPagesWithContext(feedURL, maxPages, context.Background())
+}
+
+// ParseURLPagesWithContext is like ParseURLPages but the
+// requests could be canceled or timeout via given context.
+func (f *Parser) ParseURLPagesWithContext(feedURL string, maxPages int, ctx context.Context) (*Feed, error) {
+	feed, err := f.ParseURLWithContext(feedURL, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageURL := feedURL
+	seen := map[string]bool{feedURL: true}
+	for pages := 1; maxPages <= 0 || pages < maxPages; pages++ {
+		base, err := url.Parse(pageURL)
+		if err != nil || feed.NextLink == "" {
+			break
+		}
+		pageURL = shared.ResolveURL(base, feed.NextLink)
+		if seen[pageURL] {
+			// The pages loop back on themselves
+			feed.NextLink = ""
+			break
+		}
+		seen[pageURL] = true
+
+		page, err := f.ParseURLWithContext(pageURL, ctx)
+		if err != nil {
+			return nil, err
+		}
+		feed.Items = append(feed.Items, page.Items...)
+		feed.NextLink = page.NextLink
+	}
+	return feed, nil
+}
+
+// ParseResponse parses the body of an HTTP response into the
+// universal feed type.  Responses without a 2xx status return
+// an HTTPError.  Relative links are resolved against the final
+// URL of the request, after any redirects, and parsing stops
+// if the request's context is done.  The body is left for the
+// caller to close.
+func (f *Parser) ParseResponse(resp *http.Response) (*Feed, error) {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, HTTPError{
 			StatusCode: resp.StatusCode,
@@ -138,7 +343,13 @@ func (f *Parser) ParseURLWithContext(feedURL string, ctx context.Context) (feed
 		}
 	}
 
-	return f.Parse(resp.Body)
+	var feedURL *url.URL
+	ctx := context.Background()
+	if resp.Request != nil {
+		feedURL = resp.Request.URL
+		ctx = resp.Request.Context()
+	}
+	return f.parse(resp.Body, feedURL, ctx)
 }
 
 // ParseString parses a feed XML string and into the
@@ -147,53 +358,102 @@ func (f *Parser) ParseString(feed string) (*Feed, error) {
 	return f.Parse(strings.NewReader(feed))
 }
 
-func (f *Parser) parseAtomFeed(feed io.Reader) (*Feed, error) {
-	af, err := f.ap.Parse(feed)
+func (f *Parser) parseAtomFeed(feed io.Reader, ctx context.Context) (*Feed, error) {
+	var ap atom.Parser
+	if f.ap != nil {
+		ap = *f.ap
+	}
+	ap.DateParser = f.DateParser
+	ap.StripIllegalChars = f.XMLRepair != nil && f.XMLRepair.StripIllegalChars
+	af, err := ap.ParseWithContext(feed, ctx)
 	if err != nil {
 		return nil, err
 	}
-	return f.atomTrans().Translate(af)
+	return f.translate(f.atomTrans(), af, ctx)
 }
 
-func (f *Parser) parseRSSFeed(feed io.Reader) (*Feed, error) {
-	rf, err := f.rp.Parse(feed)
+func (f *Parser) parseRSSFeed(feed io.Reader, ctx context.Context) (*Feed, error) {
+	var rp rss.Parser
+	if f.rp != nil {
+		rp = *f.rp
+	}
+	rp.DateParser = f.DateParser
+	rp.StripIllegalChars = f.XMLRepair != nil && f.XMLRepair.StripIllegalChars
+	rp.ResolveURLs = f.ResolveURLs
+	rf, err := rp.ParseWithContext(feed, ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return f.rssTrans().Translate(rf)
+	return f.translate(f.rssTrans(), rf, ctx)
 }
 
-func (f *Parser) parseJSONFeed(feed io.Reader) (*Feed, error) {
-	jf, err := f.jp.Parse(feed)
+func (f *Parser) parseJSONFeed(feed []byte, ctx context.Context) (*Feed, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var jp json.Parser
+	if f.jp != nil {
+		jp = *f.jp
+	}
+	jf, err := jp.ParseBytes(feed)
 	if err != nil {
 		return nil, err
 	}
-	return f.jsonTrans().Translate(jf)
+	return f.translate(f.jsonTrans(), jf, ctx)
+}
+
+// translate translates a feed, with the context if the
+// translator is a ContextTranslator, and runs the hooks
+// of the parser on the result.
+func (f *Parser) translate(t Translator, feed interface{}, ctx context.Context) (*Feed, error) {
+	var result *Feed
+	var err error
+	if ct, ok := t.(ContextTranslator); ok {
+		result, err = ct.TranslateWithContext(feed, ctx)
+	} else if err = ctx.Err(); err == nil {
+		result, err = t.Translate(feed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.runHooks(feed, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// translatorOptions returns the options of the
+// default translators set on the parser.
+func (f *Parser) translatorOptions() TranslatorOptions {
+	return TranslatorOptions{
+		DateParser:   f.DateParser,
+		FirstSeen:    f.FirstSeen,
+		Sanitizer:    f.Sanitizer,
+		ImageSources: f.ImageSources,
+	}
 }
 
 func (f *Parser) atomTrans() Translator {
 	if f.AtomTranslator != nil {
 		return f.AtomTranslator
 	}
-	f.AtomTranslator = &DefaultAtomTranslator{}
-	return f.AtomTranslator
+	return &DefaultAtomTranslator{TranslatorOptions: f.translatorOptions()}
 }
 
 func (f *Parser) rssTrans() Translator {
 	if f.RSSTranslator != nil {
 		return f.RSSTranslator
 	}
-	f.RSSTranslator = &DefaultRSSTranslator{}
-	return f.RSSTranslator
+	return &DefaultRSSTranslator{TranslatorOptions: f.translatorOptions()}
 }
 
 func (f *Parser) jsonTrans() Translator {
 	if f.JSONTranslator != nil {
 		return f.JSONTranslator
 	}
-	f.JSONTranslator = &DefaultJSONTranslator{}
-	return f.JSONTranslator
+	return &DefaultJSONTranslator{TranslatorOptions: f.translatorOptions()}
 }
 
 func (f *Parser) httpClient() *http.Client {
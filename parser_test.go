@@ -3,6 +3,7 @@ package gofeed_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/dates"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,9 +36,13 @@ func TestParser_Parse(t *testing.T) {
 		{"sample.json", "json", "title", false},
 		{"json10_feed.json", "json", "title", false},
 		{"json11_feed.json", "json", "title", false},
+		{"hfeed.html", "hfeed", "Feed Title", false},
+		{"activitystreams_outbox.json", "activitystreams", "", false},
 		{"unknown_feed.xml", "", "", true},
 		{"empty_feed.xml", "", "", true},
 		{"invalid.json", "", "", true},
+		{"html_page.html", "", "", true},
+		{"plain.json", "", "", true},
 	}
 
 	for _, test := range feedTests {
@@ -104,6 +110,145 @@ func TestParser_ParseString(t *testing.T) {
 	}
 }
 
+func TestParser_Parse_XMLRepair(t *testing.T) {
+	feedData := "<rss version=\"2.0\"><channel><title>Fish &amp; Chips\x0c</title>" +
+		"<item><title>Caf&eacute; & Bar</title></item>" +
+		"<item><title>Truncated"
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fish & Chips", feed.Title)
+	assert.Len(t, feed.Items, 2)
+	assert.Equal(t, "Café & Bar", feed.Items[0].Title)
+	assert.Equal(t, "Truncated", feed.Items[1].Title)
+
+	kinds := map[string]int{}
+	for _, r := range feed.Repairs {
+		kinds[r.Kind]++
+	}
+	assert.Equal(t, 1, kinds[gofeed.RepairIllegalChar])
+	assert.Equal(t, 1, kinds[gofeed.RepairHTMLEntity])
+	assert.Equal(t, 1, kinds[gofeed.RepairAmpersand])
+	assert.Equal(t, 4, kinds[gofeed.RepairTruncated])
+
+	fp.XMLRepair = nil
+	feed, err = fp.ParseString(feedData)
+	assert.NotNil(t, err)
+	assert.Nil(t, feed)
+}
+
+func TestParser_Parse_RecoverMalformed(t *testing.T) {
+	feedData := `<rss version="2.0"><channel><title>Feed Title</title>
+<item><title>1 < 2</title><description>Unclosed <b>description</item>
+<item><title>Item 2</title><link>http://example.com/2</link></item>
+</channel></rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(feedData)
+	assert.NotNil(t, err)
+	assert.Nil(t, feed)
+
+	fp.RecoverMalformed = true
+	feed, err = fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.True(t, feed.Recovered)
+	assert.Equal(t, "rss", feed.FeedType)
+	assert.Equal(t, "Feed Title", feed.Title)
+	assert.Len(t, feed.Items, 2)
+	assert.Equal(t, "1 < 2", feed.Items[0].Title)
+	assert.Equal(t, "Unclosed <b>description</b>", feed.Items[0].Description)
+	assert.Equal(t, "http://example.com/2", feed.Items[1].Link)
+
+	feed, err = fp.ParseString(`<rss><channel><title>Fine</title></channel></rss>`)
+	assert.Nil(t, err)
+	assert.False(t, feed.Recovered)
+
+	// Recovered documents are converted to UTF-8
+	feed, err = fp.ParseString("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>" +
+		"<rss><channel><title>Caf\xe9 &#233;</title><description>1 < 2</description></channel></rss>")
+	assert.Nil(t, err)
+	assert.True(t, feed.Recovered)
+	assert.Equal(t, "Café é", feed.Title)
+
+	// Extension elements keep the case of their names
+	feed, err = fp.ParseString(`<rss version="2.0" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">` +
+		`<channel><title>1 < 2</title><sy:updatePeriod>daily</sy:updatePeriod></channel></rss>`)
+	assert.Nil(t, err)
+	assert.True(t, feed.Recovered)
+	if assert.Len(t, feed.Extensions["sy"]["updatePeriod"], 1) {
+		assert.Equal(t, "daily", feed.Extensions["sy"]["updatePeriod"][0].Value)
+	}
+}
+
+func TestParser_Parse_LocalizedDates(t *testing.T) {
+	feedData := `<rss version="2.0"><channel>
+<item><pubDate>lundi 2 janvier 2006 15:04</pubDate></item>
+<item><pubDate>mardi 3 janvier 2006 09:30</pubDate></item>
+</channel></rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Len(t, feed.Items, 2)
+	assert.Equal(t, time.Date(2006, time.January, 2, 15, 4, 0, 0, time.UTC), *feed.Items[0].PublishedParsed)
+	assert.Equal(t, time.Date(2006, time.January, 3, 9, 30, 0, 0, time.UTC), *feed.Items[1].PublishedParsed)
+
+	fp.DateParser = &dates.StandardParser{Locales: []*dates.Locale{}}
+	feed, err = fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Nil(t, feed.Items[0].PublishedParsed)
+}
+
+func TestParser_Parse_ResolveURLs(t *testing.T) {
+	rssData := `<rss version="2.0"><channel>
+<link>http://example.org/blog/</link>
+<image><url>logo.png</url></image>
+<item>
+<link>posts/1.html</link>
+<description>&lt;img src="/a.png"&gt;</description>
+<enclosure url="1.mp3" length="1" type="audio/mpeg"/>
+</item>
+</channel></rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(rssData)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.org/blog/logo.png", feed.Image.URL)
+	assert.Equal(t, "http://example.org/blog/posts/1.html", feed.Items[0].Link)
+	assert.Equal(t, `<img src="http://example.org/a.png">`, feed.Items[0].Description)
+	assert.Equal(t, "http://example.org/blog/1.mp3", feed.Items[0].Enclosures[0].URL)
+
+	fp.ResolveURLs = false
+	feed, err = fp.ParseString(rssData)
+	assert.Nil(t, err)
+	assert.Equal(t, "posts/1.html", feed.Items[0].Link)
+
+	// Nor is the xml:base of RSS feeds applied
+	baseData := `<rss version="2.0" xml:base="http://example.org/"><channel>
+<item><link>posts/1.html</link></item></channel></rss>`
+	feed, err = fp.ParseString(baseData)
+	assert.Nil(t, err)
+	assert.Equal(t, "posts/1.html", feed.Items[0].Link)
+	fp.ResolveURLs = true
+	feed, err = fp.ParseString(baseData)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.org/posts/1.html", feed.Items[0].Link)
+
+	// Relative feed links are resolved against the URL the feed was fetched from
+	jsonData := `{"version": "https://jsonfeed.org/version/1.1", "title": "t",
+"home_page_url": "/blog/", "items": [{"id": "1", "url": "posts/1.html"}]}`
+
+	server, client := mockServerResponse(200, jsonData, 0)
+	defer server.Close()
+	fp = gofeed.NewParser()
+	fp.Client = client
+	feed, err = fp.ParseURL("http://example.org/feeds/main.json")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.org/blog/", feed.Link)
+	assert.Equal(t, "http://example.org/blog/posts/1.html", feed.Items[0].Link)
+}
+
 func TestParser_ParseURL_Success(t *testing.T) {
 	var feedTests = []struct {
 		file      string
@@ -183,6 +328,78 @@ func TestParser_ParseURLWithContextAndBasicAuth(t *testing.T) {
 	assert.True(t, strings.Contains(err.Error(), ctx.Err().Error()))
 }
 
+func TestParser_ParseWithContext(t *testing.T) {
+	f, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+	fp := gofeed.NewParser()
+
+	feed, err := fp.ParseWithContext(bytes.NewReader(f), context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Feed Title", feed.Title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, file := range []string{"atom10_feed.xml", "rss_feed.xml", "json11_feed.json"} {
+		path := fmt.Sprintf("testdata/parser/universal/%s", file)
+		f, _ := os.ReadFile(path)
+
+		feed, err := fp.ParseWithContext(bytes.NewReader(f), ctx)
+		assert.Nil(t, feed, file)
+		assert.True(t, errors.Is(err, context.Canceled), file)
+
+		feed, err = fp.ParseBytesWithContext(f, ctx)
+		assert.Nil(t, feed, file)
+		assert.True(t, errors.Is(err, context.Canceled), file)
+
+		feed, err = fp.ParseFileWithContext(path, ctx)
+		assert.Nil(t, feed, file)
+		assert.True(t, errors.Is(err, context.Canceled), file)
+	}
+}
+
+func TestParser_ParseBytes(t *testing.T) {
+	for _, file := range []string{"atom10_feed.xml", "rss_feed.xml", "json11_feed.json"} {
+		f, _ := os.ReadFile(fmt.Sprintf("testdata/parser/universal/%s", file))
+
+		fp := gofeed.NewParser()
+		expected, err := fp.Parse(bytes.NewReader(f))
+		assert.Nil(t, err, file)
+		actual, err := fp.ParseBytes(f)
+		assert.Nil(t, err, file)
+		assert.Equal(t, expected, actual, file)
+	}
+
+	// Repairs don't modify the caller's bytes
+	feedData := "<rss version=\"2.0\"><channel><title>Fish &amp; Chips\x0c</title></channel></rss>"
+	data := []byte(feedData)
+	feed, err := gofeed.NewParser().ParseBytes(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fish & Chips", feed.Title)
+	assert.Equal(t, feedData, string(data))
+}
+
+func TestParser_ZeroValue(t *testing.T) {
+	for _, file := range []string{"atom10_feed.xml", "rss_feed.xml", "json11_feed.json"} {
+		f, _ := os.ReadFile(fmt.Sprintf("testdata/parser/universal/%s", file))
+
+		fp := &gofeed.Parser{}
+		feed, err := fp.ParseBytes(f)
+		assert.Nil(t, err, file)
+		assert.NotNil(t, feed, file)
+	}
+}
+
+func TestParser_ParseFile(t *testing.T) {
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseFile("testdata/parser/universal/atom10_feed.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, "atom", feed.FeedType)
+	assert.Equal(t, "Feed Title", feed.Title)
+
+	feed, err = fp.ParseFile("testdata/parser/universal/missing.xml")
+	assert.Nil(t, feed)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
 // to detect race conditions, run with go test -race
 func TestParser_Concurrent(t *testing.T) {
 
@@ -270,7 +487,7 @@ func ExampleParser_ParseString() {
 	fmt.Println(feed.Title)
 }
 
-func ExampleParserWithBasicAuth_ParseURL() {
+func ExampleParser_ParseURL_basicAuth() {
 	fp := gofeed.NewParser()
 	fp.AuthConfig = &gofeed.Auth{
 		Username: "foo",
@@ -282,3 +499,61 @@ func ExampleParserWithBasicAuth_ParseURL() {
 	}
 	fmt.Println(feed.Title)
 }
+
+func TestParser_ParseHFeed(t *testing.T) {
+	f, _ := os.ReadFile("testdata/parser/universal/hfeed.html")
+
+	server, client := mockServerResponse(200, string(f), 0)
+	fp := gofeed.NewParser()
+	fp.Client = client
+	feed, err := fp.ParseURL(server.URL + "/blog/")
+	assert.Nil(t, err)
+	assert.Equal(t, "hfeed", feed.FeedType)
+	assert.Empty(t, feed.Repairs)
+	if assert.Len(t, feed.Items, 1) {
+		item := feed.Items[0]
+		assert.Equal(t, "Entry Title", item.Title)
+		assert.Equal(t, server.URL+"/posts/1", item.Link)
+		assert.Equal(t, `<p>Entry <img src="`+server.URL+`/blog/images/1.png"/> content &amp; more`+"\u00a0"+`text</p>`, item.Content)
+	}
+}
+
+func TestParser_ParseURLPages(t *testing.T) {
+	pages := map[string]string{
+		"/outbox": `{
+			"@context": "https://www.w3.org/ns/activitystreams",
+			"id": "/outbox", "type": "OrderedCollection", "first": "/outbox?page=1"
+		}`,
+		"/outbox?page=1": `{
+			"@context": "https://www.w3.org/ns/activitystreams",
+			"type": "OrderedCollectionPage", "next": "?page=2",
+			"orderedItems": [{"type": "Create", "object": {"id": "/notes/2", "type": "Note", "content": "Two"}}]
+		}`,
+		"/outbox?page=2": `{
+			"@context": "https://www.w3.org/ns/activitystreams",
+			"type": "OrderedCollectionPage", "next": "/outbox?page=1",
+			"orderedItems": [{"type": "Create", "object": {"id": "/notes/1", "type": "Note", "content": "One"}}]
+		}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, pages[r.URL.RequestURI()])
+	}))
+	defer server.Close()
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseURLPages(server.URL+"/outbox", 2)
+	assert.Nil(t, err)
+	if assert.Len(t, feed.Items, 1) {
+		assert.Equal(t, "Two", feed.Items[0].Content)
+	}
+	assert.Equal(t, server.URL+"/outbox?page=2", feed.NextLink)
+
+	// The pages loop back to the first one
+	feed, err = fp.ParseURLPages(server.URL+"/outbox", 0)
+	assert.Nil(t, err)
+	if assert.Len(t, feed.Items, 2) {
+		assert.Equal(t, "Two", feed.Items[0].Content)
+		assert.Equal(t, server.URL+"/notes/1", feed.Items[1].Link)
+	}
+	assert.Equal(t, "", feed.NextLink)
+}
@@ -0,0 +1,108 @@
+package gofeed
+
+import (
+	"bytes"
+
+	"github.com/mmcdole/gofeed/internal/shared"
+)
+
+// Kinds of repairs made by the XML repair stage.
+const (
+	// RepairIllegalChar is reported when a character that is not
+	// allowed in XML (e.g. a form feed) is removed.
+	RepairIllegalChar = shared.RepairIllegalChar
+	// RepairAmpersand is reported when a bare '&' is escaped.
+	RepairAmpersand = shared.RepairAmpersand
+	// RepairHTMLEntity is reported when an HTML named entity that
+	// XML does not define (e.g. &nbsp;) is replaced by its character.
+	RepairHTMLEntity = shared.RepairHTMLEntity
+	// RepairTruncated is reported when an element, comment or CDATA
+	// section left open at the end of the document is closed.
+	RepairTruncated = shared.RepairTruncated
+)
+
+// XMLRepairOptions selects the repairs that are applied
+// to RSS and Atom documents before they are parsed.
+type XMLRepairOptions struct {
+	// StripIllegalChars removes control characters and other
+	// characters which are not allowed in XML documents.
+	StripIllegalChars bool
+	// EscapeAmpersands escapes '&' characters which do not
+	// start a valid entity or character reference.
+	EscapeAmpersands bool
+	// ReplaceHTMLEntities replaces HTML named entities which
+	// are undefined in XML with the characters they represent.
+	ReplaceHTMLEntities bool
+	// CloseTruncated closes any elements, comments or CDATA
+	// sections which are still open at the end of the document.
+	CloseTruncated bool
+}
+
+// DefaultXMLRepairOptions returns repair options with
+// every repair enabled.
+func DefaultXMLRepairOptions() *XMLRepairOptions {
+	return &XMLRepairOptions{
+		StripIllegalChars:   true,
+		EscapeAmpersands:    true,
+		ReplaceHTMLEntities: true,
+		CloseTruncated:      true,
+	}
+}
+
+// Repair describes a single change made to a feed
+// document by the XML repair stage.
+type Repair struct {
+	Kind   string `json:"kind"`
+	Offset int    `json:"offset"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// RepairXML applies the repairs selected in opts to an XML feed
+// document and returns the repaired document along with a report
+// of each change that was made.
+func RepairXML(data []byte, opts XMLRepairOptions) ([]byte, []*Repair) {
+	fixed, changes := shared.RepairXML(data, shared.RepairOptions{
+		StripIllegalChars:   opts.StripIllegalChars,
+		EscapeAmpersands:    opts.EscapeAmpersands,
+		ReplaceHTMLEntities: opts.ReplaceHTMLEntities,
+		CloseTruncated:      opts.CloseTruncated,
+	})
+
+	var repairs []*Repair
+	for _, c := range changes {
+		repairs = append(repairs, &Repair{
+			Kind:   c.Kind,
+			Offset: c.Offset,
+			Detail: c.Detail,
+		})
+	}
+	return fixed, repairs
+}
+
+// repairXML runs the configured repair stage over data
+// if repairs are enabled and data looks like XML.
+func (f *Parser) repairXML(data []byte) ([]byte, []*Repair) {
+	if f.XMLRepair == nil || !looksLikeXML(data) || looksLikeHTML(data) {
+		return data, nil
+	}
+	return RepairXML(data, *f.XMLRepair)
+}
+
+// looksLikeHTML reports whether data, ignoring whitespace
+// and byte order marks, starts with an HTML doctype or
+// <html> tag.  HTML pages aren't repaired as XML.
+func looksLikeHTML(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \r\n\t\xef\xbb\xbf")
+	if len(trimmed) > len("<!doctype html") {
+		trimmed = trimmed[:len("<!doctype html")]
+	}
+	trimmed = bytes.ToLower(trimmed)
+	return bytes.HasPrefix(trimmed, []byte("<!doctype html")) || bytes.HasPrefix(trimmed, []byte("<html"))
+}
+
+// looksLikeXML reports whether the first character of
+// data, ignoring whitespace and byte order marks, is '<'.
+func looksLikeXML(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \r\n\t\xef\xbb\xbf")
+	return len(trimmed) > 0 && trimmed[0] == '<'
+}
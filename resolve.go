@@ -0,0 +1,84 @@
+package gofeed
+
+import (
+	"net/url"
+
+	"github.com/mmcdole/gofeed/internal/shared"
+)
+
+// resolveFeedURLs makes the relative URLs of a feed absolute.
+// The feed's own link is resolved against the URL the feed was
+// fetched from.  The links of RSS and JSON feeds are then resolved
+// against the feed's link, as those formats have no xml:base, and
+// those of Atom feeds, which have already been resolved against
+// xml:base, against the fetch URL.  Without a base nothing changes.
+func resolveFeedURLs(feed *Feed, fetchURL *url.URL) {
+	feed.Link = shared.ResolveURL(fetchURL, feed.Link)
+
+	base := fetchURL
+	if feed.FeedType != "atom" {
+		if link, err := url.Parse(feed.Link); err == nil && link.IsAbs() {
+			base = link
+		}
+	}
+	if base == nil {
+		return
+	}
+
+	feed.FeedLink = shared.ResolveURL(base, feed.FeedLink)
+	feed.NextLink = shared.ResolveURL(base, feed.NextLink)
+	resolveLinks(base, feed.Links)
+	resolveImage(base, feed.Image)
+	resolveImage(base, feed.Favicon)
+	for _, hub := range feed.Hubs {
+		if hub != nil {
+			hub.URL = shared.ResolveURL(base, hub.URL)
+		}
+	}
+
+	for _, item := range feed.Items {
+		if item == nil {
+			continue
+		}
+		item.Link = shared.ResolveURL(base, item.Link)
+		resolveLinks(base, item.Links)
+		resolveImage(base, item.Image)
+		for _, enc := range item.Enclosures {
+			if enc != nil {
+				enc.URL = shared.ResolveURL(base, enc.URL)
+			}
+		}
+		if c := item.Comments; c != nil {
+			c.Link = shared.ResolveURL(base, c.Link)
+			c.FeedURL = shared.ResolveURL(base, c.FeedURL)
+		}
+		for _, reply := range item.InReplyTo {
+			if reply != nil {
+				reply.Href = shared.ResolveURL(base, reply.Href)
+			}
+		}
+		item.Description = shared.ResolveHTMLURLs(base, item.Description)
+		item.Content = shared.ResolveHTMLURLs(base, item.Content)
+		for _, part := range item.ContentParts {
+			if part == nil {
+				continue
+			}
+			part.Src = shared.ResolveURL(base, part.Src)
+			if part.isHTML() {
+				part.Value = shared.ResolveHTMLURLs(base, part.Value)
+			}
+		}
+	}
+}
+
+func resolveLinks(base *url.URL, links []string) {
+	for i, link := range links {
+		links[i] = shared.ResolveURL(base, link)
+	}
+}
+
+func resolveImage(base *url.URL, image *Image) {
+	if image != nil {
+		image.URL = shared.ResolveURL(base, image.URL)
+	}
+}
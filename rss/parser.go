@@ -1,21 +1,46 @@
 package rss
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
 
+	"github.com/mmcdole/gofeed/dates"
 	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/gofeed/internal/shared"
 	xpp "github.com/mmcdole/goxpp"
 )
 
 // Parser is a RSS Parser
-type Parser struct{}
+type Parser struct {
+	// DateParser parses the feed's dates.  A nil
+	// DateParser uses a dates.StandardParser.
+	DateParser dates.Parser
+	// StripIllegalChars removes characters which aren't allowed
+	// in XML from documents in encodings other than UTF-8 as
+	// they are converted.
+	StripIllegalChars bool
+	// ResolveURLs resolves the relative URLs of the feed, and
+	// those in its HTML, against the xml:base in scope.
+	ResolveURLs bool
+
+	dates dates.Parser
+	base  *url.URL
+}
 
 // Parse parses an xml feed into an rss.Feed
 func (rp *Parser) Parse(feed io.Reader) (*Feed, error) {
-	p := xpp.NewXMLPullParser(feed, false, shared.NewReaderLabel)
+	return rp.ParseWithContext(feed, context.Background())
+}
+
+// ParseWithContext is like Parse but stops with the error of
+// the context once it's done.  The context is checked as the
+// document is read.
+func (rp *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Feed, error) {
+	rp = rp.forFeed()
+	p := xpp.NewXMLPullParser(shared.NewContextReader(ctx, feed), false, shared.CharsetReader(rp.StripIllegalChars))
 
 	_, err := shared.FindRoot(p)
 	if err != nil {
@@ -25,12 +50,40 @@ func (rp *Parser) Parse(feed io.Reader) (*Feed, error) {
 	return rp.parseRoot(p)
 }
 
+// pushBase applies the xml:base of the current start tag and
+// returns a func restoring the enclosing base.  The base stack
+// of the pull parser can't be relied on as goxpp pops it at
+// every end tag.
+func (rp *Parser) pushBase(p *xpp.XMLPullParser) func() {
+	parent := rp.base
+	rp.base = rp.xmlBase(p)
+	return func() { rp.base = parent }
+}
+
+// xmlBase returns the base URL of the current start tag,
+// or nil if URLs aren't resolved.
+func (rp *Parser) xmlBase(p *xpp.XMLPullParser) *url.URL {
+	if !rp.ResolveURLs {
+		return nil
+	}
+	return shared.XMLBase(p, rp.base)
+}
+
+// forFeed returns a copy of the parser holding
+// the date parser for a single feed.
+func (rp *Parser) forFeed() *Parser {
+	c := *rp
+	c.dates = dates.ForFeed(rp.DateParser)
+	return &c
+}
+
 func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 	rssErr := p.Expect(xpp.StartTag, "rss")
 	rdfErr := p.Expect(xpp.StartTag, "rdf")
 	if rssErr != nil && rdfErr != nil {
 		return nil, fmt.Errorf("%s or %s", rssErr.Error(), rdfErr.Error())
 	}
+	defer rp.pushBase(p)()
 
 	// Items found in feed root
 	var channel *Feed
@@ -118,6 +171,7 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 	if err = p.Expect(xpp.StartTag, "channel"); err != nil {
 		return nil, err
 	}
+	defer rp.pushBase(p)()
 
 	rss = &Feed{}
 	rss.Items = []*Item{}
@@ -195,7 +249,7 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 					return nil, err
 				}
 				rss.PubDate = result
-				date, err := shared.ParseDate(result)
+				date, err := rp.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					rss.PubDateParsed = &utcDate
@@ -206,7 +260,7 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 					return nil, err
 				}
 				rss.LastBuildDate = result
-				date, err := shared.ParseDate(result)
+				date, err := rp.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					rss.LastBuildDateParsed = &utcDate
@@ -316,6 +370,7 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 	if err = p.Expect(xpp.StartTag, "item"); err != nil {
 		return nil, err
 	}
+	defer rp.pushBase(p)()
 
 	item = &Item{}
 	extensions := ext.Extensions{}
@@ -350,7 +405,7 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 				}
 				item.Title = result
 			} else if name == "description" {
-				result, err := shared.ParseText(p)
+				result, err := rp.parseHTML(p)
 				if err != nil {
 					return nil, err
 				}
@@ -359,7 +414,7 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 				space := strings.TrimSpace(p.Space)
 				prefix := shared.PrefixForNamespace(space, p)
 				if prefix == "content" {
-					result, err := shared.ParseText(p)
+					result, err := rp.parseHTML(p)
 					if err != nil {
 						return nil, err
 					}
@@ -379,7 +434,7 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 				}
 				item.Author = result
 			} else if name == "comments" {
-				result, err := shared.ParseText(p)
+				result, err := rp.parseURL(p)
 				if err != nil {
 					return nil, err
 				}
@@ -390,7 +445,7 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 					return nil, err
 				}
 				item.PubDate = result
-				date, err := shared.ParseDate(result)
+				date, err := rp.dates.Parse(result)
 				if err == nil {
 					utcDate := date.UTC()
 					item.PubDateParsed = &utcDate
@@ -465,6 +520,7 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 }
 
 func (rp *Parser) parseLink(p *xpp.XMLPullParser) (url string, err error) {
+	base := rp.xmlBase(p)
 	href := p.Attribute("href")
 	url, err = shared.ParseText(p)
 	if err != nil {
@@ -473,7 +529,29 @@ func (rp *Parser) parseLink(p *xpp.XMLPullParser) (url string, err error) {
 	if url == "" && href != "" {
 		url = href
 	}
-	return url, err
+	return shared.ResolveURL(base, url), err
+}
+
+// parseURL parses an element holding a URL and resolves
+// it against the xml:base in scope.
+func (rp *Parser) parseURL(p *xpp.XMLPullParser) (string, error) {
+	base := rp.xmlBase(p)
+	result, err := shared.ParseText(p)
+	if err != nil {
+		return "", err
+	}
+	return shared.ResolveURL(base, result), nil
+}
+
+// parseHTML parses an element holding HTML and resolves the
+// URLs in it against the xml:base in scope.
+func (rp *Parser) parseHTML(p *xpp.XMLPullParser) (string, error) {
+	base := rp.xmlBase(p)
+	result, err := shared.ParseText(p)
+	if err != nil {
+		return "", err
+	}
+	return shared.ResolveHTMLURLs(base, result), nil
 }
 
 func (rp *Parser) parseSource(p *xpp.XMLPullParser) (source *Source, err error) {
@@ -482,7 +560,7 @@ func (rp *Parser) parseSource(p *xpp.XMLPullParser) (source *Source, err error)
 	}
 
 	source = &Source{}
-	source.URL = p.Attribute("url")
+	source.URL = shared.ResolveURL(rp.xmlBase(p), p.Attribute("url"))
 
 	result, err := shared.ParseText(p)
 	if err != nil {
@@ -502,7 +580,7 @@ func (rp *Parser) parseEnclosure(p *xpp.XMLPullParser) (enclosure *Enclosure, er
 	}
 
 	enclosure = &Enclosure{}
-	enclosure.URL = p.Attribute("url")
+	enclosure.URL = shared.ResolveURL(rp.xmlBase(p), p.Attribute("url"))
 	enclosure.Length = p.Attribute("length")
 	enclosure.Type = p.Attribute("type")
 
@@ -525,6 +603,7 @@ func (rp *Parser) parseImage(p *xpp.XMLPullParser) (image *Image, err error) {
 	if err = p.Expect(xpp.StartTag, "image"); err != nil {
 		return nil, err
 	}
+	defer rp.pushBase(p)()
 
 	image = &Image{}
 
@@ -542,7 +621,7 @@ func (rp *Parser) parseImage(p *xpp.XMLPullParser) (image *Image, err error) {
 			name := strings.ToLower(p.Name)
 
 			if name == "url" {
-				result, err := shared.ParseText(p)
+				result, err := rp.parseURL(p)
 				if err != nil {
 					return nil, err
 				}
@@ -554,7 +633,7 @@ func (rp *Parser) parseImage(p *xpp.XMLPullParser) (image *Image, err error) {
 				}
 				image.Title = result
 			} else if name == "link" {
-				result, err := shared.ParseText(p)
+				result, err := rp.parseURL(p)
 				if err != nil {
 					return nil, err
 				}
@@ -2,7 +2,9 @@ package rss_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,7 +28,7 @@ func TestParser_Parse(t *testing.T) {
 		f, _ := os.ReadFile(ff)
 
 		// Parse actual feed
-		fp := &rss.Parser{}
+		fp := &rss.Parser{ResolveURLs: true}
 		actual, _ := fp.Parse(bytes.NewReader(f))
 
 		// Get json encoded expected feed result
@@ -46,3 +48,50 @@ func TestParser_Parse(t *testing.T) {
 }
 
 // TODO: Examples
+
+func TestParser_ParseWithContext(t *testing.T) {
+	fp := &rss.Parser{}
+	feed, err := fp.ParseWithContext(strings.NewReader(`<rss version="2.0"><channel><title>Title</title></channel></rss>`), context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", feed.Title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	feed, err = fp.ParseWithContext(strings.NewReader(`<rss version="2.0"><channel><title>Title</title></channel></rss>`), ctx)
+	assert.Nil(t, feed)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestParser_Parse_StripIllegalChars(t *testing.T) {
+	feedData := "<?xml version=\"1.0\" encoding=\"windows-1252\"?>" +
+		"<rss version=\"2.0\"><channel><title>Caf\xe9\x0c</title></channel></rss>"
+
+	fp := &rss.Parser{}
+	feed, err := fp.Parse(strings.NewReader(feedData))
+	assert.NotNil(t, err)
+	assert.Nil(t, feed)
+
+	fp.StripIllegalChars = true
+	feed, err = fp.Parse(strings.NewReader(feedData))
+	assert.Nil(t, err)
+	assert.Equal(t, "Café", feed.Title)
+}
+
+func TestParser_Parse_ResolveURLsDisabled(t *testing.T) {
+	feedData := `<rss version="2.0" xml:base="http://example.org/"><channel>
+<link>blog/</link><item><link>post/1</link><description>&lt;img src="a.png"&gt;</description></item>
+</channel></rss>`
+
+	fp := &rss.Parser{}
+	feed, err := fp.Parse(strings.NewReader(feedData))
+	assert.Nil(t, err)
+	assert.Equal(t, "blog/", feed.Link)
+	assert.Equal(t, "post/1", feed.Items[0].Link)
+	assert.Equal(t, `<img src="a.png">`, feed.Items[0].Description)
+
+	fp.ResolveURLs = true
+	feed, err = fp.Parse(strings.NewReader(feedData))
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.org/blog/", feed.Link)
+	assert.Equal(t, "http://example.org/post/1", feed.Items[0].Link)
+}
@@ -0,0 +1,289 @@
+package gofeed
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mmcdole/gofeed/internal/shared"
+	"golang.org/x/net/html"
+)
+
+// HTML elements which never have content.
+var voidElements = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"param":  true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
+
+// SanitizePolicy is an allowlist of the HTML which survives
+// sanitization.  Anything that isn't allowed is removed.
+type SanitizePolicy struct {
+	// Elements maps the names of allowed elements to the
+	// attributes allowed on them.  The tags of other elements
+	// are removed but their text is kept, except for the
+	// elements in DropContent.
+	Elements map[string][]string
+
+	// GlobalAttributes are allowed on all allowed elements.
+	GlobalAttributes []string
+
+	// DropContent lists elements which, unless allowed by
+	// Elements, are removed along with everything inside them.
+	DropContent []string
+
+	// URLSchemes lists the schemes allowed in attributes
+	// holding URLs.  Relative URLs are always allowed.
+	URLSchemes []string
+
+	// StripTrackingPixels removes images which are at
+	// most one pixel wide or high.
+	StripTrackingPixels bool
+}
+
+// DefaultSanitizePolicy returns a policy which keeps text
+// formatting, links, lists, tables, images and media, and
+// removes scripts, styles, event handlers, embedded frames,
+// forms, non-web URLs and tracking pixels.
+func DefaultSanitizePolicy() *SanitizePolicy {
+	return &SanitizePolicy{
+		Elements: map[string][]string{
+			"a":          {"href", "title"},
+			"abbr":       {"title"},
+			"audio":      {"src", "controls", "loop", "preload"},
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"caption":    nil,
+			"cite":       nil,
+			"code":       nil,
+			"dd":         nil,
+			"del":        {"cite", "datetime"},
+			"details":    nil,
+			"dfn":        nil,
+			"div":        nil,
+			"dl":         nil,
+			"dt":         nil,
+			"em":         nil,
+			"figcaption": nil,
+			"figure":     nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "title", "width", "height"},
+			"ins":        {"cite", "datetime"},
+			"kbd":        nil,
+			"li":         nil,
+			"mark":       nil,
+			"ol":         {"start", "type"},
+			"p":          nil,
+			"pre":        nil,
+			"q":          {"cite"},
+			"s":          nil,
+			"samp":       nil,
+			"small":      nil,
+			"source":     {"src", "type"},
+			"span":       nil,
+			"strong":     nil,
+			"sub":        nil,
+			"summary":    nil,
+			"sup":        nil,
+			"table":      nil,
+			"tbody":      nil,
+			"td":         {"colspan", "rowspan"},
+			"tfoot":      nil,
+			"th":         {"colspan", "rowspan", "scope"},
+			"thead":      nil,
+			"time":       {"datetime"},
+			"tr":         nil,
+			"u":          nil,
+			"ul":         nil,
+			"video":      {"src", "poster", "controls", "loop", "preload", "width", "height"},
+		},
+		GlobalAttributes: []string{"dir", "lang"},
+		DropContent: []string{
+			"applet", "button", "embed", "frame", "frameset", "head",
+			"iframe", "math", "noembed", "noframes", "noscript", "object",
+			"script", "select", "style", "svg", "template", "textarea", "title",
+		},
+		URLSchemes:          []string{"http", "https", "mailto"},
+		StripTrackingPixels: true,
+	}
+}
+
+// Sanitize returns a safe copy of an HTML fragment.  The result
+// only holds the elements, attributes and URLs allowed by the
+// policy, and all of its elements are properly closed.
+func (p *SanitizePolicy) Sanitize(fragment string) string {
+	z := html.NewTokenizer(strings.NewReader(fragment))
+
+	var out strings.Builder
+	var open []string
+	dropped, depth := "", 0
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		tn, _ := z.TagName()
+		name := string(tn)
+
+		if depth > 0 {
+			// Skip everything up to the end of the dropped element
+			if name == dropped && tt == html.StartTagToken {
+				depth++
+			} else if name == dropped && tt == html.EndTagToken {
+				depth--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			out.WriteString(html.EscapeString(string(z.Text())))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			allowed, ok := p.Elements[name]
+			if !ok {
+				if contains(p.DropContent, name) && tt == html.StartTagToken && !voidElements[name] {
+					dropped, depth = name, 1
+				}
+				continue
+			}
+			attrs, keep := p.sanitizeAttrs(z, name, allowed)
+			if !keep {
+				continue
+			}
+			if voidElements[name] {
+				out.WriteString("<" + name + attrs + "/>")
+			} else {
+				out.WriteString("<" + name + attrs + ">")
+				open = append(open, name)
+			}
+
+		case html.EndTagToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					closeTags(&out, open[i:])
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+
+	closeTags(&out, open)
+	return out.String()
+}
+
+// sanitize applies the policy if there is one.
+func (p *SanitizePolicy) sanitize(fragment string) string {
+	if p == nil || fragment == "" {
+		return fragment
+	}
+	return p.Sanitize(fragment)
+}
+
+// sanitizeItem sanitizes the HTML of an item.
+func (p *SanitizePolicy) sanitizeItem(item *Item) {
+	item.Description = p.sanitize(item.Description)
+	item.Content = p.sanitize(item.Content)
+	for _, part := range item.ContentParts {
+		if part.isHTML() {
+			part.Value = p.sanitize(part.Value)
+		}
+	}
+}
+
+// sanitizeAttrs returns the allowed attributes of the current
+// tag, and false if the element should be removed altogether.
+func (p *SanitizePolicy) sanitizeAttrs(z *html.Tokenizer, name string, allowed []string) (string, bool) {
+	var attrs strings.Builder
+	var width, height string
+	seen := map[string]bool{}
+
+	for more := true; more; {
+		var key, val []byte
+		key, val, more = z.TagAttr()
+		k, v := string(key), string(val)
+		if k == "" || seen[k] {
+			continue
+		}
+		if !contains(allowed, k) && !contains(p.GlobalAttributes, k) {
+			continue
+		}
+		if shared.IsHTMLURIAttr(k) && !p.allowURL(v) {
+			continue
+		}
+		seen[k] = true
+		switch k {
+		case "width":
+			width = v
+		case "height":
+			height = v
+		}
+		attrs.WriteString(" " + k + `="` + html.EscapeString(v) + `"`)
+	}
+
+	if name == "img" && p.StripTrackingPixels && (isPixel(width) || isPixel(height)) {
+		return "", false
+	}
+	return attrs.String(), true
+}
+
+// allowURL reports whether the URL is relative or
+// uses one of the allowed schemes.
+func (p *SanitizePolicy) allowURL(u string) bool {
+	// Browsers ignore whitespace and control
+	// characters such as in "java\tscript:"
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, u)
+
+	i := strings.IndexAny(u, ":/?#")
+	if i <= 0 || u[i] != ':' {
+		return true
+	}
+	return contains(p.URLSchemes, strings.ToLower(u[:i]))
+}
+
+func isPixel(size string) bool {
+	size = strings.TrimSuffix(strings.TrimSpace(size), "px")
+	n, err := strconv.Atoi(size)
+	return err == nil && n <= 1
+}
+
+func closeTags(out *strings.Builder, names []string) {
+	for i := len(names) - 1; i >= 0; i-- {
+		out.WriteString("</" + names[i] + ">")
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package gofeed_test
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizePolicy_Sanitize(t *testing.T) {
+	tests := []struct {
+		html string
+		safe string
+	}{
+		{"", ""},
+		{"plain &amp; simple", "plain &amp; simple"},
+		{"<p>Hello <b>world</b></p>", "<p>Hello <b>world</b></p>"},
+
+		// Scripts, styles and frames are removed with their content
+		{"<p>a<script>alert(1)</script>b</p>", "<p>ab</p>"},
+		{"<style>p { color: red }</style><p>text</p>", "<p>text</p>"},
+		{"<iframe src=\"http://example.com\">fallback</iframe>", ""},
+		{"<svg><svg></svg><script>x</script></svg>after", "after"},
+
+		// Unknown elements are unwrapped
+		{"<font color=red>text</font>", "text"},
+		{"<form><input name=x>text</form>", "text"},
+
+		// Event handlers and other attributes are dropped
+		{"<p onclick=\"evil()\" class=\"x\" lang=\"en\">text</p>", "<p lang=\"en\">text</p>"},
+		{"<img src=\"/a.png\" onerror=\"evil()\" alt=\"a\">", "<img src=\"/a.png\" alt=\"a\"/>"},
+
+		// Unsafe URLs
+		{"<a href=\"javascript:alert(1)\">link</a>", "<a>link</a>"},
+		{"<a href=\"java\tscript:alert(1)\">link</a>", "<a>link</a>"},
+		{"<a href=\"&#106;avascript:alert(1)\">link</a>", "<a>link</a>"},
+		{"<a href=\"JAVASCRIPT:alert(1)\">link</a>", "<a>link</a>"},
+		{"<img src=\"data:image/png;base64,AAAA\">", "<img/>"},
+		{"<a href=\"http://example.com/?q=1&amp;r=2\">link</a>", "<a href=\"http://example.com/?q=1&amp;r=2\">link</a>"},
+		{"<a href=\"mailto:a@example.com\">mail</a>", "<a href=\"mailto:a@example.com\">mail</a>"},
+		{"<a href=\"../relative:path\">rel</a>", "<a href=\"../relative:path\">rel</a>"},
+
+		// Tracking pixels
+		{"<p>text<img src=\"http://t.example.com/p.gif\" width=\"1\" height=\"1\"></p>", "<p>text</p>"},
+		{"<img src=\"http://example.com/a.png\" width=\"0\">", ""},
+		{"<img src=\"http://example.com/a.png\" width=\"100\" height=\"50\">", "<img src=\"http://example.com/a.png\" width=\"100\" height=\"50\"/>"},
+
+		// Unbalanced markup
+		{"<p><b>unclosed", "<p><b>unclosed</b></p>"},
+		{"</div>stray</p>", "stray"},
+		{"<ul><li>one<li>two</ul>", "<ul><li>one<li>two</li></li></ul>"},
+		{"a &lt;script&gt; tag", "a &lt;script&gt; tag"},
+	}
+
+	policy := gofeed.DefaultSanitizePolicy()
+	for _, test := range tests {
+		assert.Equal(t, test.safe, policy.Sanitize(test.html), "sanitizing %q", test.html)
+	}
+}
+
+func TestSanitizePolicy_Custom(t *testing.T) {
+	policy := &gofeed.SanitizePolicy{
+		Elements:   map[string][]string{"a": {"href"}},
+		URLSchemes: []string{"https"},
+	}
+	assert.Equal(t, "<a href=\"https://example.com\">a</a> <a>b</a> c",
+		policy.Sanitize("<a href=\"https://example.com\">a</a> <a href=\"http://example.com\">b</a> <em>c</em>"))
+}
+
+func TestParser_Parse_Sanitizer(t *testing.T) {
+	feedData := `<rss version="2.0"><channel><item>
+<description><![CDATA[<p onclick="x()">Hi<script>alert(1)</script></p>]]></description>
+</item></channel></rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Equal(t, `<p onclick="x()">Hi<script>alert(1)</script></p>`, feed.Items[0].Description)
+
+	fp.Sanitizer = gofeed.DefaultSanitizePolicy()
+	feed, err = fp.ParseString(feedData)
+	assert.Nil(t, err)
+	assert.Equal(t, "<p>Hi</p>", feed.Items[0].Description)
+
+	// HTML content parts are sanitized too, text parts aren't
+	jsonData := `{"version": "https://jsonfeed.org/version/1.1", "items": [
+		{"content_html": "<p>Hi<script>alert(1)</script></p>", "content_text": "<script>"}
+	]}`
+	feed, err = fp.ParseString(jsonData)
+	assert.Nil(t, err)
+	assert.Equal(t, []*gofeed.ContentPart{
+		{Type: gofeed.ContentTypeHTML, Value: "<p>Hi</p>"},
+		{Type: gofeed.ContentTypeText, Value: "<script>"},
+	}, feed.Items[0].ContentParts)
+
+	// As are parts with an HTML or XML MIME type
+	atomData := `<feed xmlns="http://www.w3.org/2005/Atom">
+<entry><content type="text/html">&lt;p&gt;Hi&lt;img src="x.png" onerror="x()"&gt;&lt;/p&gt;</content></entry>
+<entry><content type="image/svg+xml"><svg><script>alert(1)</script></svg></content></entry>
+</feed>`
+	feed, err = fp.ParseString(atomData)
+	assert.Nil(t, err)
+	assert.Equal(t, []*gofeed.ContentPart{
+		{Type: gofeed.ContentTypeHTML, Value: `<p>Hi<img src="x.png"/></p>`},
+	}, feed.Items[0].ContentParts)
+	assert.NotContains(t, feed.Items[1].ContentParts[0].Value, "script")
+	assert.Equal(t, "image/svg+xml", feed.Items[1].ContentParts[0].Type)
+}
+
+func TestSanitizePolicy_AllowDropped(t *testing.T) {
+	policy := gofeed.DefaultSanitizePolicy()
+	policy.Elements["iframe"] = []string{"src"}
+	assert.Equal(t, `<iframe src="https://example.com/video"></iframe>`,
+		policy.Sanitize(`<iframe src="https://example.com/video" onload="x()"></iframe>`))
+}
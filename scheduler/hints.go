@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// Hints are a publisher's indications of how often
+// a feed should be polled.
+type Hints struct {
+	// TTL is how long the feed may be cached,
+	// from the RSS <ttl> element.
+	TTL time.Duration
+	// SkipHours are the hours of the day, in UTC,
+	// in which the feed shouldn't be polled.
+	SkipHours []int
+	// SkipDays are the days of the week, in UTC,
+	// on which the feed shouldn't be polled.
+	SkipDays []time.Weekday
+	// UpdatePeriod is how often the feed is updated,
+	// from sy:updatePeriod and sy:updateFrequency.
+	UpdatePeriod time.Duration
+	// Expires is when the cached response goes stale,
+	// from the Cache-Control and Expires headers.
+	Expires time.Time
+}
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// RSSHints returns the hints of an RSS feed.  Values
+// which can't be understood are ignored.
+func RSSHints(feed *rss.Feed) Hints {
+	var hints Hints
+	if feed == nil {
+		return hints
+	}
+
+	if ttl, err := strconv.Atoi(strings.TrimSpace(feed.TTL)); err == nil && ttl > 0 {
+		hints.TTL = time.Duration(ttl) * time.Minute
+	}
+	for _, h := range feed.SkipHours {
+		hour, err := strconv.Atoi(strings.TrimSpace(h))
+		if err != nil || hour < 0 || hour > 24 {
+			continue
+		}
+		// Some feeds number the hours from 1 to 24
+		hints.SkipHours = append(hints.SkipHours, hour%24)
+	}
+	for _, d := range feed.SkipDays {
+		if day, ok := weekdays[strings.ToLower(strings.TrimSpace(d))]; ok {
+			hints.SkipDays = append(hints.SkipDays, day)
+		}
+	}
+	hints.UpdatePeriod = UpdatePeriod(feed.Extensions)
+	return hints
+}
+
+// UpdatePeriod returns the update period given by the syndication
+// module (sy:updatePeriod divided by sy:updateFrequency) in a
+// feed's extensions, or zero if there is none.
+func UpdatePeriod(extensions ext.Extensions) time.Duration {
+	sy := extensions["sy"]
+	if sy == nil {
+		return 0
+	}
+
+	period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(firstValue(sy["updatePeriod"])))]
+	if !ok {
+		return 0
+	}
+	frequency, err := strconv.Atoi(strings.TrimSpace(firstValue(sy["updateFrequency"])))
+	if err != nil || frequency < 1 {
+		frequency = 1
+	}
+	return period / time.Duration(frequency)
+}
+
+// CacheExpiry returns when a response with the given headers,
+// received at now, goes stale.  Cache-Control max-age takes
+// precedence over Expires.  The zero time is returned if the
+// response may not be cached or has no expiry.
+func CacheExpiry(header http.Header, now time.Time) time.Time {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-cache" || directive == "no-store" {
+			return time.Time{}
+		}
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		maxAge, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil {
+			continue
+		}
+		age, _ := strconv.Atoi(header.Get("Age"))
+		return now.Add(time.Duration(maxAge-age) * time.Second)
+	}
+
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		return expires
+	}
+	return time.Time{}
+}
+
+func firstValue(extensions []ext.Extension) string {
+	if len(extensions) == 0 {
+		return ""
+	}
+	return extensions[0].Value
+}
@@ -0,0 +1,71 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/mmcdole/gofeed/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSSHints(t *testing.T) {
+	feed := &rss.Feed{
+		TTL:       " 60 ",
+		SkipHours: []string{"0", "7", "24", "25", "x"},
+		SkipDays:  []string{"Saturday", " sunday", "Someday"},
+		Extensions: ext.Extensions{"sy": {
+			"updatePeriod":    {{Value: "daily"}},
+			"updateFrequency": {{Value: "4"}},
+		}},
+	}
+
+	hints := scheduler.RSSHints(feed)
+	assert.Equal(t, time.Hour, hints.TTL)
+	assert.Equal(t, []int{0, 7, 0}, hints.SkipHours)
+	assert.Equal(t, []time.Weekday{time.Saturday, time.Sunday}, hints.SkipDays)
+	assert.Equal(t, 6*time.Hour, hints.UpdatePeriod)
+
+	assert.Equal(t, scheduler.Hints{}, scheduler.RSSHints(nil))
+	assert.Equal(t, scheduler.Hints{}, scheduler.RSSHints(&rss.Feed{TTL: "-5"}))
+}
+
+func TestUpdatePeriod(t *testing.T) {
+	sy := func(period, frequency string) ext.Extensions {
+		return ext.Extensions{"sy": {
+			"updatePeriod":    {{Value: period}},
+			"updateFrequency": {{Value: frequency}},
+		}}
+	}
+
+	assert.Equal(t, time.Hour, scheduler.UpdatePeriod(sy("hourly", "")))
+	assert.Equal(t, 7*24*time.Hour, scheduler.UpdatePeriod(sy("Weekly", "0")))
+	assert.Equal(t, 30*time.Minute, scheduler.UpdatePeriod(sy("hourly", "2")))
+	assert.Equal(t, time.Duration(0), scheduler.UpdatePeriod(sy("sometimes", "1")))
+	assert.Equal(t, time.Duration(0), scheduler.UpdatePeriod(nil))
+}
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	expires := "Mon, 02 Jan 2006 18:00:00 GMT"
+
+	tests := []struct {
+		header http.Header
+		want   time.Time
+	}{
+		{http.Header{}, time.Time{}},
+		{http.Header{"Cache-Control": {"public, max-age=600"}}, now.Add(10 * time.Minute)},
+		{http.Header{"Cache-Control": {"max-age=600"}, "Age": {"100"}}, now.Add(500 * time.Second)},
+		{http.Header{"Cache-Control": {"max-age=600"}, "Expires": {expires}}, now.Add(10 * time.Minute)},
+		{http.Header{"Expires": {expires}}, time.Date(2006, time.January, 2, 18, 0, 0, 0, time.UTC)},
+		{http.Header{"Cache-Control": {"no-cache"}, "Expires": {expires}}, time.Time{}},
+		{http.Header{"Expires": {"0"}}, time.Time{}},
+	}
+
+	for _, test := range tests {
+		got := scheduler.CacheExpiry(test.header, now)
+		assert.True(t, test.want.Equal(got), "CacheExpiry(%v) = %s; want %s", test.header, got, test.want)
+	}
+}
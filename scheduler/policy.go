@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Policy decides how often feeds are polled.
+type Policy struct {
+	// MinInterval is the shortest time between polls.
+	MinInterval time.Duration
+	// MaxInterval is the longest time between polls.
+	// It takes precedence over the feed's hints.
+	MaxInterval time.Duration
+	// DefaultInterval is used for feeds whose posting
+	// frequency can't be estimated.
+	DefaultInterval time.Duration
+	// Backoff multiplies the interval for each
+	// consecutive poll which found no new items.
+	Backoff float64
+	// RecentItems is the number of newest items the
+	// posting frequency is estimated from.
+	RecentItems int
+}
+
+// DefaultPolicy returns a policy which polls feeds between
+// every 5 minutes and once a day, backing off by half again
+// for every poll without new items.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MinInterval:     5 * time.Minute,
+		MaxInterval:     24 * time.Hour,
+		DefaultInterval: time.Hour,
+		Backoff:         1.5,
+		RecentItems:     10,
+	}
+}
+
+// NextPoll returns when a feed should next be polled after a
+// successful poll at now.  The interval starts from the feed's
+// posting interval, or DefaultInterval if that's unknown, grows
+// with the number of polls in a row which found no new items and
+// is never shorter than the feed's TTL or update period.  The poll
+// is postponed until the cached response expires, but not past
+// MaxInterval, and then out of any skipped hours and days.
+func (p *Policy) NextPoll(now time.Time, hints Hints, posting time.Duration, unchanged int) time.Time {
+	interval := posting
+	if interval <= 0 {
+		interval = p.DefaultInterval
+	}
+	interval = scale(interval, math.Pow(p.Backoff, float64(unchanged)))
+	if hints.TTL > interval {
+		interval = hints.TTL
+	}
+	if hints.UpdatePeriod > interval {
+		interval = hints.UpdatePeriod
+	}
+	interval = p.clamp(interval)
+
+	next := now.Add(interval)
+	if hints.Expires.After(next) {
+		next = hints.Expires
+		if limit := now.Add(p.MaxInterval); p.MaxInterval > 0 && next.After(limit) {
+			next = limit
+		}
+	}
+	return skip(next, hints)
+}
+
+// RetryAt returns when a feed should next be polled after
+// failures consecutive failed polls, the last of them at now.
+// The interval doubles with each failure.
+func (p *Policy) RetryAt(now time.Time, failures int) time.Time {
+	interval := scale(p.MinInterval, math.Pow(2, float64(failures-1)))
+	return now.Add(p.clamp(interval))
+}
+
+// PostingInterval estimates the time between the posts of a
+// feed from the dates of its RecentItems newest items, or returns
+// zero if it has fewer than two dated items.  Items dated by
+// their feed or when they were first seen don't count.
+func (p *Policy) PostingInterval(feed *gofeed.Feed) time.Duration {
+	if feed == nil {
+		return 0
+	}
+
+	var dates []time.Time
+	for _, item := range feed.Items {
+		if item == nil || item.DateParsed == nil {
+			continue
+		}
+		if item.DateSource == gofeed.DateSourceFeed || item.DateSource == gofeed.DateSourceFirstSeen {
+			continue
+		}
+		dates = append(dates, *item.DateParsed)
+	}
+	if len(dates) < 2 {
+		return 0
+	}
+
+	sort.Slice(dates, func(i, k int) bool { return dates[i].After(dates[k]) })
+	if p.RecentItems > 1 && len(dates) > p.RecentItems {
+		dates = dates[:p.RecentItems]
+	}
+	return dates[0].Sub(dates[len(dates)-1]) / time.Duration(len(dates)-1)
+}
+
+// scale multiplies a duration, saturating
+// rather than overflowing.
+func scale(d time.Duration, factor float64) time.Duration {
+	if f := float64(d) * factor; f < math.MaxInt64 {
+		return time.Duration(f)
+	}
+	return math.MaxInt64
+}
+
+func (p *Policy) clamp(interval time.Duration) time.Duration {
+	if interval < p.MinInterval {
+		return p.MinInterval
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		return p.MaxInterval
+	}
+	return interval
+}
+
+// skip moves t to the start of the first hour, at or after it,
+// which isn't in the skipped hours or days.  Hints which skip
+// every hour of the week are ignored.
+func skip(t time.Time, hints Hints) time.Time {
+	if len(hints.SkipHours) == 0 && len(hints.SkipDays) == 0 {
+		return t
+	}
+
+	next := t
+	for i := 0; i < 7*24; i++ {
+		utc := next.UTC()
+		if !skipped(utc, hints) {
+			return next
+		}
+		next = utc.Truncate(time.Hour).Add(time.Hour)
+	}
+	return t
+}
+
+func skipped(t time.Time, hints Hints) bool {
+	for _, day := range hints.SkipDays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	for _, hour := range hints.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_NextPoll(t *testing.T) {
+	// A Monday
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	p := scheduler.DefaultPolicy()
+
+	tests := []struct {
+		name      string
+		hints     scheduler.Hints
+		posting   time.Duration
+		unchanged int
+		want      time.Time
+	}{
+		{"default", scheduler.Hints{}, 0, 0, now.Add(time.Hour)},
+		{"posting", scheduler.Hints{}, 2 * time.Hour, 0, now.Add(2 * time.Hour)},
+		{"min", scheduler.Hints{}, time.Minute, 0, now.Add(5 * time.Minute)},
+		{"backoff", scheduler.Hints{}, 0, 2, now.Add(2*time.Hour + 15*time.Minute)},
+		{"max", scheduler.Hints{}, 0, 20, now.Add(24 * time.Hour)},
+		{"ttl", scheduler.Hints{TTL: 3 * time.Hour}, time.Hour, 0, now.Add(3 * time.Hour)},
+		{"update period", scheduler.Hints{UpdatePeriod: 4 * time.Hour}, time.Hour, 0, now.Add(4 * time.Hour)},
+		{"expires", scheduler.Hints{Expires: now.Add(90 * time.Minute)}, 0, 0, now.Add(90 * time.Minute)},
+		{"expires past max", scheduler.Hints{Expires: now.Add(48 * time.Hour)}, 0, 0, now.Add(24 * time.Hour)},
+		{"skip hours", scheduler.Hints{SkipHours: []int{16, 17}}, 0, 0, time.Date(2006, time.January, 2, 18, 0, 0, 0, time.UTC)},
+		{"skip days", scheduler.Hints{SkipDays: []time.Weekday{time.Monday}}, 0, 0, time.Date(2006, time.January, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		got := p.NextPoll(now, test.hints, test.posting, test.unchanged)
+		assert.True(t, test.want.Equal(got), "%s: NextPoll = %s; want %s", test.name, got, test.want)
+	}
+
+	// Skipping every hour is ignored
+	all := scheduler.Hints{SkipDays: []time.Weekday{0, 1, 2, 3, 4, 5, 6}}
+	assert.Equal(t, now.Add(time.Hour), p.NextPoll(now, all, 0, 0))
+
+	// Without a maximum interval, Expires isn't limited
+	p.MaxInterval = 0
+	expires := scheduler.Hints{Expires: now.Add(48 * time.Hour)}
+	assert.Equal(t, now.Add(48*time.Hour), p.NextPoll(now, expires, 0, 0))
+}
+
+func TestPolicy_RetryAt(t *testing.T) {
+	now := time.Unix(0, 0)
+	p := scheduler.DefaultPolicy()
+
+	assert.Equal(t, now.Add(5*time.Minute), p.RetryAt(now, 1))
+	assert.Equal(t, now.Add(20*time.Minute), p.RetryAt(now, 3))
+	assert.Equal(t, now.Add(24*time.Hour), p.RetryAt(now, 100))
+}
+
+func TestPolicy_PostingInterval(t *testing.T) {
+	item := func(hours int, source string) *gofeed.Item {
+		date := time.Unix(0, 0).Add(time.Duration(hours) * time.Hour)
+		return &gofeed.Item{DateParsed: &date, DateSource: source}
+	}
+	p := scheduler.DefaultPolicy()
+	p.RecentItems = 3
+
+	feed := &gofeed.Feed{Items: []*gofeed.Item{
+		item(100, gofeed.DateSourceFeed),
+		item(10, gofeed.DateSourcePublished),
+		item(16, gofeed.DateSourcePublished),
+		nil,
+		item(4, gofeed.DateSourceUpdated),
+		item(0, gofeed.DateSourcePublished),
+		{},
+	}}
+	assert.Equal(t, 6*time.Hour, p.PostingInterval(feed))
+
+	assert.Equal(t, time.Duration(0), p.PostingInterval(&gofeed.Feed{Items: feed.Items[:2]}))
+	assert.Equal(t, time.Duration(0), p.PostingInterval(nil))
+}
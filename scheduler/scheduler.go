@@ -0,0 +1,454 @@
+// Package scheduler polls feeds at intervals chosen from their
+// publishers' hints (RSS ttl, skipHours and skipDays, the
+// syndication module's sy:updatePeriod and HTTP cache headers)
+// and from how often they're observed to post.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// ErrPolling is the error of a poll of a feed
+// which is already being polled.
+var ErrPolling = errors.New("feed is already being polled")
+
+// State is what the scheduler knows about a feed
+// between polls.
+type State struct {
+	// ETag and LastModified are sent back with the next
+	// request so that unchanged feeds aren't downloaded.
+	ETag         string
+	LastModified string
+	// LastPoll and NextPoll are the times of the last
+	// and next polls, and LastSuccess that of the last
+	// poll which didn't fail.
+	LastPoll    time.Time
+	NextPoll    time.Time
+	LastSuccess time.Time
+	// Unchanged is the number of polls in a row
+	// which found no new items.
+	Unchanged int
+	// Failures is the number of polls in a row
+	// which failed.
+	Failures int
+	// Items holds the identities of the items
+	// seen in the last poll.
+	Items map[string]bool
+	// Hints and PostingInterval are those of the feed
+	// when it was last modified.  See Policy.PostingInterval.
+	Hints           Hints
+	PostingInterval time.Duration
+}
+
+// Result is the outcome of polling a feed.
+type Result struct {
+	URL string
+	// Feed is the parsed feed, or nil if it wasn't
+	// modified or the poll failed.
+	Feed *gofeed.Feed
+	// NewItems are the items of Feed which
+	// weren't in the previous poll.
+	NewItems []*gofeed.Item
+	// StatusCode is the HTTP status of the response,
+	// or zero if there was none.
+	StatusCode  int
+	NotModified bool
+	// FinalURL is the URL of the response after any
+	// redirects, and PermanentRedirect reports whether
+	// there were redirects and all were permanent.
+	FinalURL          string
+	PermanentRedirect bool
+	Hints             Hints
+	Err               error
+	Time              time.Time
+	NextPoll          time.Time
+}
+
+// Scheduler polls a set of feeds, each when it is due,
+// with a pool of workers.
+type Scheduler struct {
+	// Parser fetches and parses the feeds.  Its Client,
+	// UserAgent and AuthConfig are used for requests.
+	Parser *gofeed.Parser
+	// Policy decides when feeds are polled.
+	Policy *Policy
+	// Workers is the number of feeds polled at once.
+	Workers int
+	// Handler, if set, is called with the result of every
+	// poll.  It's called from the worker goroutines.
+	Handler func(*Result)
+
+	mu    sync.Mutex
+	feeds map[string]*entry
+	wake  chan struct{}
+	now   func() time.Time
+}
+
+type entry struct {
+	state   State
+	polling bool
+}
+
+// New creates a scheduler using the parser, or a
+// gofeed.NewParser if parser is nil, and the DefaultPolicy.
+func New(parser *gofeed.Parser) *Scheduler {
+	if parser == nil {
+		parser = gofeed.NewParser()
+	}
+	return &Scheduler{
+		Parser:  parser,
+		Policy:  DefaultPolicy(),
+		Workers: 4,
+	}
+}
+
+// Add schedules a feed to be polled as soon as possible.
+// Feeds which are already scheduled are left unchanged.
+func (s *Scheduler) Add(feedURL string) {
+	s.mu.Lock()
+	s.init()
+	if _, ok := s.feeds[feedURL]; !ok {
+		s.feeds[feedURL] = &entry{}
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+// AddWithState schedules a feed with a state saved from an
+// earlier run, replacing the state of a scheduled feed.
+func (s *Scheduler) AddWithState(feedURL string, state State) {
+	s.mu.Lock()
+	s.init()
+	if e, ok := s.feeds[feedURL]; ok {
+		e.state = state
+	} else {
+		s.feeds[feedURL] = &entry{state: state}
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+// Remove stops polling a feed.
+func (s *Scheduler) Remove(feedURL string) {
+	s.mu.Lock()
+	delete(s.feeds, feedURL)
+	s.mu.Unlock()
+}
+
+// State returns a copy of the state of a scheduled feed.
+func (s *Scheduler) State(feedURL string) (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.feeds[feedURL]; ok {
+		state := e.state
+		if state.Items != nil {
+			state.Items = make(map[string]bool, len(e.state.Items))
+			for id := range e.state.Items {
+				state.Items[id] = true
+			}
+		}
+		return state, true
+	}
+	return State{}, false
+}
+
+// Run polls the scheduled feeds as they fall due
+// until the context is done, and returns its error.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.init()
+	s.mu.Unlock()
+
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for feedURL := range jobs {
+				s.poll(ctx, feedURL)
+			}
+		}()
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	for {
+		due, wait := s.due()
+		for i, feedURL := range due {
+			select {
+			case jobs <- feedURL:
+			case <-ctx.Done():
+				s.release(due[i:])
+				return ctx.Err()
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// Poll fetches a scheduled feed now and schedules its next poll.
+// A feed which isn't scheduled is polled but not scheduled.  A
+// feed which is already being polled isn't polled again, and the
+// result has the error ErrPolling.
+func (s *Scheduler) Poll(ctx context.Context, feedURL string) *Result {
+	s.mu.Lock()
+	s.init()
+	if e, ok := s.feeds[feedURL]; ok {
+		if e.polling {
+			s.mu.Unlock()
+			return &Result{URL: feedURL, Err: ErrPolling, Time: s.now()}
+		}
+		e.polling = true
+	}
+	s.mu.Unlock()
+	return s.poll(ctx, feedURL)
+}
+
+// poll polls a feed which has been marked as being polled.
+func (s *Scheduler) poll(ctx context.Context, feedURL string) *Result {
+	s.mu.Lock()
+	var state State
+	if e, ok := s.feeds[feedURL]; ok {
+		state = e.state
+	}
+	s.mu.Unlock()
+
+	result := s.fetch(ctx, feedURL, &state)
+
+	s.mu.Lock()
+	if e, ok := s.feeds[feedURL]; ok {
+		e.state = state
+		e.polling = false
+	}
+	s.mu.Unlock()
+	s.signal()
+
+	if s.Handler != nil {
+		s.Handler(result)
+	}
+	return result
+}
+
+// fetch polls a feed and updates its state.
+func (s *Scheduler) fetch(ctx context.Context, feedURL string, state *State) *Result {
+	now := s.now()
+	state.LastPoll = now
+	result := &Result{URL: feedURL, Time: now}
+
+	resp, err := s.request(ctx, feedURL, state)
+	if err == nil {
+		defer resp.Body.Close()
+		result.StatusCode = resp.StatusCode
+		result.FinalURL, result.PermanentRedirect = redirects(resp)
+		result.Hints = state.Hints
+		if resp.StatusCode == http.StatusNotModified {
+			result.NotModified = true
+		} else {
+			result.Feed, result.Hints, err = s.parse(resp)
+		}
+		result.Hints.Expires = CacheExpiry(resp.Header, now)
+	}
+
+	if err != nil {
+		result.Err = err
+		state.Failures++
+		state.NextPoll = s.Policy.RetryAt(now, state.Failures)
+		result.NextPoll = state.NextPoll
+		return result
+	}
+
+	state.Failures = 0
+	state.LastSuccess = now
+	if result.Feed != nil {
+		state.ETag = resp.Header.Get("ETag")
+		state.LastModified = resp.Header.Get("Last-Modified")
+		result.NewItems = newItems(result.Feed, state)
+		state.Hints = result.Hints
+		state.PostingInterval = s.Policy.PostingInterval(result.Feed)
+	}
+	if len(result.NewItems) == 0 {
+		state.Unchanged++
+	} else {
+		state.Unchanged = 0
+	}
+
+	state.NextPoll = s.Policy.NextPoll(now, result.Hints, state.PostingInterval, state.Unchanged)
+	result.NextPoll = state.NextPoll
+	return result
+}
+
+// request makes a conditional GET request for a feed.
+func (s *Scheduler) request(ctx context.Context, feedURL string, state *State) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", s.Parser.UserAgent)
+	if auth := s.Parser.AuthConfig; auth != nil && auth.Username != "" && auth.Password != "" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	client := s.Parser.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// redirects returns the final URL of a response and whether
+// it was reached through permanent redirects only.
+func redirects(resp *http.Response) (string, bool) {
+	if resp.Request == nil {
+		return "", false
+	}
+	permanent := resp.Request.Response != nil
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		code := req.Response.StatusCode
+		if code != http.StatusMovedPermanently && code != http.StatusPermanentRedirect {
+			permanent = false
+		}
+	}
+	return resp.Request.URL.String(), permanent
+}
+
+// parse parses a response and returns the feed and its hints.
+func (s *Scheduler) parse(resp *http.Response) (*gofeed.Feed, Hints, error) {
+	// Capture the hints of RSS feeds, which don't
+	// survive translation, from the native feed.
+	var hints Hints
+	fp := *s.Parser
+	fp.FeedHooks = append(fp.FeedHooks[:len(fp.FeedHooks):len(fp.FeedHooks)],
+		func(native interface{}, feed *gofeed.Feed) error {
+			if rf, ok := native.(*rss.Feed); ok {
+				hints = RSSHints(rf)
+			}
+			return nil
+		})
+
+	feed, err := fp.ParseResponse(resp)
+	if err != nil {
+		return nil, hints, err
+	}
+	if feed.FeedType != "rss" {
+		hints.UpdatePeriod = UpdatePeriod(feed.Extensions)
+	}
+	return feed, hints, nil
+}
+
+// newItems returns the items of the feed which weren't
+// seen in the last poll and remembers those which were.
+func newItems(feed *gofeed.Feed, state *State) []*gofeed.Item {
+	var items []*gofeed.Item
+	seen := map[string]bool{}
+	for _, item := range feed.Items {
+		if item == nil {
+			continue
+		}
+		id := item.Identity()
+		if !state.Items[id] && !seen[id] {
+			items = append(items, item)
+		}
+		seen[id] = true
+	}
+	state.Items = seen
+	return items
+}
+
+// due marks the feeds which are due as being polled and returns
+// them, along with how long to wait for the next feed to fall due.
+func (s *Scheduler) due() ([]string, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	wait := s.Policy.MaxInterval
+	if wait <= 0 {
+		wait = time.Hour
+	}
+	var due []string
+	for feedURL, e := range s.feeds {
+		if e.polling {
+			continue
+		}
+		if !e.state.NextPoll.After(now) {
+			e.polling = true
+			due = append(due, feedURL)
+		} else if d := e.state.NextPoll.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	return due, wait
+}
+
+// release unmarks feeds which were due
+// but weren't handed to a worker.
+func (s *Scheduler) release(feedURLs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, feedURL := range feedURLs {
+		if e, ok := s.feeds[feedURL]; ok {
+			e.polling = false
+		}
+	}
+}
+
+// signal wakes Run to look for feeds which are due.
+func (s *Scheduler) signal() {
+	s.mu.Lock()
+	s.init()
+	wake := s.wake
+	s.mu.Unlock()
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// init sets up a scheduler which wasn't created with New.
+// It must be called with the lock held.
+func (s *Scheduler) init() {
+	if s.feeds == nil {
+		s.feeds = map[string]*entry{}
+	}
+	if s.wake == nil {
+		s.wake = make(chan struct{}, 1)
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	if s.Parser == nil {
+		s.Parser = gofeed.NewParser()
+	}
+	if s.Policy == nil {
+		s.Policy = DefaultPolicy()
+	}
+}
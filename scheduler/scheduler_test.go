@@ -0,0 +1,197 @@
+package scheduler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+const rssFeed = `<rss version="2.0"><channel>
+<ttl>90</ttl>
+<item><guid>1</guid><pubDate>Mon, 02 Jan 2006 10:00:00 GMT</pubDate></item>
+<item><guid>2</guid><pubDate>Mon, 02 Jan 2006 12:00:00 GMT</pubDate></item>
+</channel></rss>`
+
+const atomFeed = `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">
+<sy:updatePeriod>daily</sy:updatePeriod>
+<entry><id>1</id></entry>
+</feed>`
+
+func feedServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Cache-Control", "max-age=60")
+			io.WriteString(w, rssFeed)
+		case "/atom":
+			io.WriteString(w, atomFeed)
+		case "/moved":
+			http.Redirect(w, r, "/atom", http.StatusMovedPermanently)
+		case "/temporary":
+			http.Redirect(w, r, "/moved", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+}
+
+func TestScheduler_Poll(t *testing.T) {
+	server := feedServer()
+	defer server.Close()
+
+	s := scheduler.New(nil)
+	feedURL := server.URL + "/rss"
+	s.Add(feedURL)
+
+	result := s.Poll(context.Background(), feedURL)
+	assert.Nil(t, result.Err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Len(t, result.NewItems, 2)
+	assert.Equal(t, 90*time.Minute, result.Hints.TTL)
+	assert.False(t, result.Hints.Expires.IsZero())
+
+	state, ok := s.State(feedURL)
+	assert.True(t, ok)
+	assert.Equal(t, `"v1"`, state.ETag)
+	assert.Equal(t, 2*time.Hour, state.PostingInterval)
+	assert.Equal(t, state.LastPoll.Add(2*time.Hour), state.NextPoll)
+
+	// The second poll is conditional and keeps the feed's hints
+	result = s.Poll(context.Background(), feedURL)
+	assert.Nil(t, result.Err)
+	assert.True(t, result.NotModified)
+	assert.Nil(t, result.Feed)
+	assert.Equal(t, 90*time.Minute, result.Hints.TTL)
+
+	state, _ = s.State(feedURL)
+	assert.Equal(t, 1, state.Unchanged)
+	assert.Equal(t, state.LastPoll.Add(3*time.Hour), state.NextPoll)
+
+	// The state is a copy
+	state.Items["3"] = true
+	state, _ = s.State(feedURL)
+	assert.Len(t, state.Items, 2)
+}
+
+func TestScheduler_Poll_InProgress(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, rssFeed)
+	}))
+	defer server.Close()
+
+	s := scheduler.New(nil)
+	s.Add(server.URL)
+
+	done := make(chan *scheduler.Result)
+	go func() { done <- s.Poll(context.Background(), server.URL) }()
+	<-started
+
+	result := s.Poll(context.Background(), server.URL)
+	assert.Equal(t, scheduler.ErrPolling, result.Err)
+	close(release)
+	assert.Nil(t, (<-done).Err)
+
+	state, _ := s.State(server.URL)
+	assert.Len(t, state.Items, 2)
+}
+
+func TestScheduler_Poll_UpdatePeriod(t *testing.T) {
+	server := feedServer()
+	defer server.Close()
+
+	result := scheduler.New(nil).Poll(context.Background(), server.URL+"/atom")
+	assert.Nil(t, result.Err)
+	assert.Equal(t, "atom", result.Feed.FeedType)
+	assert.Equal(t, 24*time.Hour, result.Hints.UpdatePeriod)
+}
+
+func TestScheduler_Poll_Redirects(t *testing.T) {
+	server := feedServer()
+	defer server.Close()
+	s := scheduler.New(nil)
+
+	result := s.Poll(context.Background(), server.URL+"/atom")
+	assert.Equal(t, server.URL+"/atom", result.FinalURL)
+	assert.False(t, result.PermanentRedirect)
+
+	result = s.Poll(context.Background(), server.URL+"/moved")
+	assert.Equal(t, server.URL+"/atom", result.FinalURL)
+	assert.True(t, result.PermanentRedirect)
+
+	result = s.Poll(context.Background(), server.URL+"/temporary")
+	assert.Equal(t, server.URL+"/atom", result.FinalURL)
+	assert.False(t, result.PermanentRedirect)
+}
+
+func TestScheduler_Poll_Failure(t *testing.T) {
+	server := feedServer()
+	defer server.Close()
+
+	s := scheduler.New(nil)
+	feedURL := server.URL + "/missing"
+	s.Add(feedURL)
+
+	for i := 1; i <= 2; i++ {
+		result := s.Poll(context.Background(), feedURL)
+		assert.Equal(t, gofeed.HTTPError{StatusCode: 500, Status: "500 Internal Server Error"}, result.Err)
+
+		state, _ := s.State(feedURL)
+		assert.Equal(t, i, state.Failures)
+		assert.Equal(t, s.Policy.RetryAt(state.LastPoll, i), state.NextPoll)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	server := feedServer()
+	defer server.Close()
+
+	var mu sync.Mutex
+	polled := map[string]int{}
+	done := make(chan struct{})
+
+	s := scheduler.New(nil)
+	s.Workers = 2
+	s.Handler = func(result *scheduler.Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		polled[result.URL]++
+		if len(polled) == 2 {
+			close(done)
+		}
+	}
+	s.Add(server.URL + "/rss")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- s.Run(ctx) }()
+
+	// Feeds added while running are polled straight away
+	s.Add(server.URL + "/atom")
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("feeds were not polled")
+	}
+	cancel()
+	assert.Equal(t, context.Canceled, <-errc)
+
+	// Neither feed is due again yet
+	assert.Equal(t, map[string]int{server.URL + "/rss": 1, server.URL + "/atom": 1}, polled)
+}
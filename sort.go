@@ -0,0 +1,69 @@
+package gofeed
+
+import (
+	"sort"
+	"time"
+)
+
+// SortKey selects the date items are sorted by.
+type SortKey int
+
+const (
+	// SortByPublished sorts items by PublishedParsed.
+	SortByPublished SortKey = iota
+	// SortByUpdated sorts items by UpdatedParsed.
+	SortByUpdated
+	// SortByDate sorts items by DateParsed, their
+	// effective date.
+	SortByDate
+)
+
+// SortOrder is the direction items are sorted in.
+type SortOrder int
+
+const (
+	// Ascending sorts items from oldest to newest.
+	Ascending SortOrder = iota
+	// Descending sorts items from newest to oldest.
+	Descending
+)
+
+// SortItems sorts the items of the feed by the given date.
+// See SortItems.
+func (f Feed) SortItems(key SortKey, order SortOrder) {
+	SortItems(f.Items, key, order)
+}
+
+// SortItems sorts items by the given date.  Items without
+// that date are placed after all others, whatever the order,
+// and the sort is stable so such items keep their order.
+func SortItems(items []*Item, key SortKey, order SortOrder) {
+	sort.SliceStable(items, func(i, k int) bool {
+		return lessDate(itemDate(items[i], key), itemDate(items[k], key), order)
+	})
+}
+
+func itemDate(item *Item, key SortKey) *time.Time {
+	if item == nil {
+		return nil
+	}
+	switch key {
+	case SortByUpdated:
+		return item.UpdatedParsed
+	case SortByDate:
+		return item.DateParsed
+	}
+	return item.PublishedParsed
+}
+
+// lessDate reports whether a sorts before b.
+// Missing dates sort last.
+func lessDate(a, b *time.Time, order SortOrder) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	if order == Descending {
+		return a.After(*b)
+	}
+	return a.Before(*b)
+}
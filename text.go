@@ -0,0 +1,289 @@
+package gofeed
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// HTML elements which start a new paragraph
+	blockElements = map[string]bool{
+		"address":    true,
+		"article":    true,
+		"aside":      true,
+		"blockquote": true,
+		"dd":         true,
+		"details":    true,
+		"div":        true,
+		"dl":         true,
+		"dt":         true,
+		"fieldset":   true,
+		"figcaption": true,
+		"figure":     true,
+		"footer":     true,
+		"form":       true,
+		"h1":         true,
+		"h2":         true,
+		"h3":         true,
+		"h4":         true,
+		"h5":         true,
+		"h6":         true,
+		"header":     true,
+		"hr":         true,
+		"main":       true,
+		"nav":        true,
+		"ol":         true,
+		"p":          true,
+		"pre":        true,
+		"section":    true,
+		"table":      true,
+		"ul":         true,
+	}
+
+	// HTML elements whose content is never displayed
+	hiddenElements = map[string]bool{
+		"head":     true,
+		"noscript": true,
+		"script":   true,
+		"style":    true,
+		"template": true,
+		"title":    true,
+	}
+)
+
+// Text returns the item's content, or its description
+// if it has no content, as plain text.  See HTMLToText.
+func (i *Item) Text() string {
+	if i.Content != "" {
+		return HTMLToText(i.Content)
+	}
+	return HTMLToText(i.Description)
+}
+
+// Summary returns a plain text summary of the item of at
+// most maxLen characters, taken from its description or,
+// if it has no description, its content.  See Summarize.
+func (i *Item) Summary(maxLen int) string {
+	text := HTMLToText(i.Description)
+	if text == "" {
+		text = HTMLToText(i.Content)
+	}
+	return Summarize(text, maxLen)
+}
+
+// HTMLToText converts an HTML fragment to readable plain text.
+// Entities are decoded, whitespace is collapsed except inside
+// <pre>, block elements are separated by blank lines, <br>
+// starts a new line and list items are prefixed with a bullet
+// or, in ordered lists, their number.
+func HTMLToText(fragment string) string {
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	w := &textWriter{}
+	var lists []int // item counters of open lists, -1 for unordered
+	hidden, pre := 0, 0
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tn, _ := z.TagName()
+		name := string(tn)
+
+		switch tt {
+		case html.TextToken:
+			if hidden == 0 {
+				w.text(string(z.Text()), pre > 0)
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			selfClosing := tt == html.SelfClosingTagToken
+			switch {
+			case hiddenElements[name]:
+				if !selfClosing {
+					hidden++
+				}
+			case name == "br":
+				w.lineBreak(1)
+			case name == "li":
+				w.lineBreak(1)
+				w.bullet(lists)
+				if len(lists) > 0 && lists[len(lists)-1] >= 0 {
+					lists[len(lists)-1]++
+				}
+			case name == "tr":
+				w.lineBreak(1)
+			case name == "td" || name == "th":
+				w.space = true
+			case blockElements[name]:
+				w.lineBreak(paragraphBreak(name, lists))
+				if selfClosing {
+					break
+				}
+				if name == "pre" {
+					pre++
+					w.preStart = true
+				} else if name == "ul" {
+					lists = append(lists, -1)
+				} else if name == "ol" {
+					lists = append(lists, 1)
+				}
+			}
+
+		case html.EndTagToken:
+			switch {
+			case hiddenElements[name]:
+				if hidden > 0 {
+					hidden--
+				}
+			case blockElements[name]:
+				if (name == "ul" || name == "ol") && len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				w.lineBreak(paragraphBreak(name, lists))
+				if name == "pre" && pre > 0 {
+					pre--
+				}
+			}
+		}
+	}
+
+	return strings.TrimSpace(w.b.String())
+}
+
+// paragraphBreak returns the number of line breaks around a
+// block element.  Lists nested in another list aren't set
+// apart by blank lines.
+func paragraphBreak(name string, lists []int) int {
+	if (name == "ul" || name == "ol") && len(lists) > 0 {
+		return 1
+	}
+	return 2
+}
+
+// textWriter collapses whitespace and line breaks.
+type textWriter struct {
+	b        strings.Builder
+	breaks   int
+	space    bool
+	preStart bool
+}
+
+// lineBreak requests n line breaks before the next text.
+func (w *textWriter) lineBreak(n int) {
+	if n > w.breaks {
+		w.breaks = n
+	}
+}
+
+// flush writes the pending line breaks or space.
+// Nothing is written at the start of the text.
+func (w *textWriter) flush() {
+	if w.b.Len() > 0 {
+		if w.breaks > 0 {
+			w.b.WriteString(strings.Repeat("\n", w.breaks))
+		} else if w.space {
+			w.b.WriteByte(' ')
+		}
+	}
+	w.breaks, w.space = 0, false
+}
+
+func (w *textWriter) text(s string, pre bool) {
+	if pre {
+		// A newline right after <pre> is ignored
+		if w.preStart {
+			s = strings.TrimPrefix(s, "\n")
+		}
+		w.preStart = false
+		w.flush()
+		w.b.WriteString(s)
+		return
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			w.space = true
+			continue
+		}
+		w.flush()
+		w.b.WriteRune(r)
+	}
+}
+
+// bullet starts a list item, indented by the depth of the list.
+func (w *textWriter) bullet(lists []int) {
+	w.flush()
+	marker := "•"
+	if n := len(lists); n > 0 {
+		w.b.WriteString(strings.Repeat("  ", n-1))
+		if lists[n-1] >= 0 {
+			marker = strconv.Itoa(lists[n-1]) + "."
+		}
+	}
+	w.b.WriteString(marker)
+	w.space = true
+}
+
+// Summarize shortens text to at most maxLen characters.  Runs of
+// whitespace, including line breaks, become single spaces.  Text
+// which is too long is cut at the end of the last sentence that
+// fits, if that keeps at least half of it, or else after the last
+// whole word, followed by an ellipsis.  Text is never cut in the
+// middle of a multi-byte character.
+func Summarize(text string, maxLen int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if maxLen <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+
+	// Leave room for the ellipsis
+	runes := []rune(text)
+	cut := string(runes[:maxLen-1])
+	next := runes[maxLen-1]
+
+	if i := lastSentenceEnd(cut, next); i >= len(cut)/2 {
+		return cut[:i]
+	}
+	if unicode.IsSpace(next) {
+		return strings.TrimRightFunc(cut, isSpaceOrPunct) + "…"
+	}
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		return strings.TrimRightFunc(cut[:i], isSpaceOrPunct) + "…"
+	}
+	return cut + "…"
+}
+
+// lastSentenceEnd returns the index just past the last sentence
+// terminator in s which is followed by a space, or -1.  Full-width
+// terminators, as used in CJK text, need no space.
+func lastSentenceEnd(s string, next rune) int {
+	if strings.ContainsRune(".!?", lastRune(s)) && unicode.IsSpace(next) {
+		return len(s)
+	}
+	end := -1
+	for _, p := range []string{". ", "! ", "? ", "。", "！", "？"} {
+		if i := strings.LastIndex(s, p); i != -1 {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			if i+size > end {
+				end = i + size
+			}
+		}
+	}
+	return end
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+func isSpaceOrPunct(r rune) bool {
+	return unicode.IsSpace(r) || r == ',' || r == ';' || r == ':' || r == '-'
+}
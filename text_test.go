@@ -0,0 +1,72 @@
+package gofeed_test
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		html string
+		text string
+	}{
+		{"", ""},
+		{"plain text", "plain text"},
+		{"  lots   of\n\twhitespace  ", "lots of whitespace"},
+		{"Fish &amp; Chips &eacute;&#233; &lt;tag&gt;", "Fish & Chips éé <tag>"},
+		{"<p>One</p><p>Two</p>", "One\n\nTwo"},
+		{"<h1>Title</h1>Text<br>More<br/>Lines", "Title\n\nText\nMore\nLines"},
+		{"a <b>bold</b> <i>word</i>", "a bold word"},
+		{"<p>Text<script>alert('x')</script><style>p {}</style></p>", "Text"},
+		{"<ul><li>one</li><li>two</li></ul>", "• one\n• two"},
+		{"<ol><li>one<li>two</ol>after", "1. one\n2. two\n\nafter"},
+		{"<ul><li>a<ul><li>nested</li></ul></li><li>b</li></ul>", "• a\n  • nested\n• b"},
+		{"<pre>\n  code\n    indented</pre>", "code\n    indented"},
+		{"<table><tr><td>a</td><td>b</td></tr><tr><td>c</td></tr></table>", "a b\nc"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.text, gofeed.HTMLToText(test.html), "converting %q", test.html)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		text    string
+		maxLen  int
+		summary string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"line\n\nbreaks  and   spaces", 100, "line breaks and spaces"},
+		{"anything", 0, ""},
+		{"The first sentence. The second sentence is long.", 30, "The first sentence."},
+		{"Cut this text on a word boundary please", 20, "Cut this text on a…"},
+		{"Trailing punctuation, here", 22, "Trailing punctuation…"},
+		{"Supercalifragilistic", 10, "Supercali…"},
+		{"日本語のテキスト", 5, "日本語の…"},
+		{"日本語のテキストです。とても長い文章です。", 15, "日本語のテキストです。"},
+	}
+
+	for _, test := range tests {
+		summary := gofeed.Summarize(test.text, test.maxLen)
+		assert.Equal(t, test.summary, summary, "summarizing %q to %d", test.text, test.maxLen)
+		assert.True(t, utf8.ValidString(summary))
+		assert.True(t, utf8.RuneCountInString(summary) <= test.maxLen)
+	}
+}
+
+func TestItem_TextAndSummary(t *testing.T) {
+	item := &gofeed.Item{
+		Description: "<p>A short summary. With more detail.</p>",
+		Content:     "<p>The full content.</p><p>Second paragraph.</p>",
+	}
+	assert.Equal(t, "The full content.\n\nSecond paragraph.", item.Text())
+	assert.Equal(t, "A short summary.", item.Summary(30))
+
+	item.Description = ""
+	assert.Equal(t, "The full content. Second paragraph.", item.Summary(50))
+}
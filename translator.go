@@ -2,13 +2,18 @@ package gofeed
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed/activitystreams"
 	"github.com/mmcdole/gofeed/atom"
+	"github.com/mmcdole/gofeed/dates"
 	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/hfeed"
 	"github.com/mmcdole/gofeed/internal/shared"
 	"github.com/mmcdole/gofeed/json"
 	"github.com/mmcdole/gofeed/rss"
@@ -21,21 +26,74 @@ type Translator interface {
 	Translate(feed interface{}) (*Feed, error)
 }
 
+// ContextTranslator is a Translator which can be cancelled.
+// Parser uses TranslateWithContext for translators which
+// implement it, as the default translators do.
+type ContextTranslator interface {
+	Translator
+	TranslateWithContext(feed interface{}, ctx context.Context) (*Feed, error)
+}
+
+// TranslatorOptions are the options shared by the
+// default translators.
+type TranslatorOptions struct {
+	// DateParser parses the dates which the translator
+	// reads from extensions or raw date strings.  A nil
+	// DateParser uses a dates.StandardParser.
+	DateParser dates.Parser
+
+	// FirstSeen returns the time an item was first seen.  It
+	// dates items which have no date of their own and belong
+	// to a feed without a date.  A nil FirstSeen, or a zero
+	// time, leaves those items undated.
+	FirstSeen func(item *Item) time.Time
+
+	// Sanitizer, if set, removes unsafe HTML from the
+	// Content and Description of items.
+	Sanitizer *SanitizePolicy
+
+	// ImageSources lists where the image of an item is looked
+	// for, in order.  A nil ImageSources uses DefaultImageSources.
+	ImageSources []ImageSource
+
+	dates dates.Parser
+	ctx   context.Context
+}
+
+// forFeed returns a copy of the options holding the
+// date parser and context for a single feed.
+func (o TranslatorOptions) forFeed(ctx context.Context) TranslatorOptions {
+	o.ctx = ctx
+	o.dates = dates.ForFeed(o.DateParser)
+	return o
+}
+
 // DefaultRSSTranslator converts an rss.Feed struct
 // into the generic Feed struct.
 //
 // This default implementation defines a set of
 // mapping rules between rss.Feed -> Feed
 // for each of the fields in Feed.
-type DefaultRSSTranslator struct{}
+type DefaultRSSTranslator struct {
+	TranslatorOptions
+}
 
 // Translate converts an RSS feed into the universal
 // feed type.
 func (t *DefaultRSSTranslator) Translate(feed interface{}) (*Feed, error) {
+	return t.TranslateWithContext(feed, context.Background())
+}
+
+// TranslateWithContext is like Translate but stops with the
+// error of the context once it's done.
+func (t *DefaultRSSTranslator) TranslateWithContext(feed interface{}, ctx context.Context) (*Feed, error) {
 	rss, found := feed.(*rss.Feed)
 	if !found {
 		return nil, fmt.Errorf("Feed did not match expected type of *rss.Feed")
 	}
+	c := *t
+	c.TranslatorOptions = t.forFeed(ctx)
+	t = &c
 
 	result := &Feed{}
 	result.Title = t.translateFeedTitle(rss)
@@ -55,11 +113,15 @@ func (t *DefaultRSSTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.Generator = t.translateFeedGenerator(rss)
 	result.Categories = t.translateFeedCategories(rss)
 	result.Items = t.translateFeedItems(rss)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	result.ITunesExt = rss.ITunesExt
 	result.DublinCoreExt = rss.DublinCoreExt
 	result.Extensions = rss.Extensions
 	result.FeedVersion = rss.Version
 	result.FeedType = "rss"
+	fillItemDates(result, t.FirstSeen)
 	return result, nil
 }
 
@@ -68,6 +130,7 @@ func (t *DefaultRSSTranslator) translateFeedItem(rssItem *rss.Item) (item *Item)
 	item.Title = t.translateItemTitle(rssItem)
 	item.Description = t.translateItemDescription(rssItem)
 	item.Content = t.translateItemContent(rssItem)
+	item.ContentParts = t.translateItemContentParts(rssItem)
 	item.Link = t.translateItemLink(rssItem)
 	item.Links = t.translateItemLinks(rssItem)
 	item.Published = t.translateItemPublished(rssItem)
@@ -75,13 +138,18 @@ func (t *DefaultRSSTranslator) translateFeedItem(rssItem *rss.Item) (item *Item)
 	item.Author = t.translateItemAuthor(rssItem)
 	item.Authors = t.translateItemAuthors(rssItem)
 	item.GUID = t.translateItemGUID(rssItem)
-	item.Image = t.translateItemImage(rssItem)
 	item.Categories = t.translateItemCategories(rssItem)
 	item.Enclosures = t.translateItemEnclosures(rssItem)
+	item.Source = t.translateItemSource(rssItem)
+	item.Comments = itemComments(rssItem.Comments, "", rssItem.Extensions)
+	item.InReplyTo = itemInReplyTo(rssItem.Extensions)
 	item.DublinCoreExt = rssItem.DublinCoreExt
 	item.ITunesExt = rssItem.ITunesExt
 	item.Extensions = rssItem.Extensions
 	item.Custom = rssItem.Custom
+	item.Image = selectImage(item, nil, t.ImageSources)
+	t.Sanitizer.sanitizeItem(item)
+	item.DateParsed, item.DateSource = t.translateItemDate(rssItem)
 	return
 }
 
@@ -157,7 +225,7 @@ func (t *DefaultRSSTranslator) translateFeedUpdatedParsed(rss *rss.Feed) (update
 		updated = rss.LastBuildDateParsed
 	} else if rss.DublinCoreExt != nil && rss.DublinCoreExt.Date != nil {
 		dateText := t.firstEntry(rss.DublinCoreExt.Date)
-		date, err := shared.ParseDate(dateText)
+		date, err := t.dates.Parse(dateText)
 		if err == nil {
 			updated = &date
 		}
@@ -224,8 +292,12 @@ func (t *DefaultRSSTranslator) translateFeedLanguage(rss *rss.Feed) (language st
 func (t *DefaultRSSTranslator) translateFeedImage(rss *rss.Feed) *Image {
 	if rss.Image != nil {
 		return &Image{
-			Title: rss.Image.Title,
-			URL:   rss.Image.URL,
+			Title:       rss.Image.Title,
+			URL:         rss.Image.URL,
+			Link:        rss.Image.Link,
+			Description: rss.Image.Description,
+			Width:       parseDimension(rss.Image.Width),
+			Height:      parseDimension(rss.Image.Height),
 		}
 	}
 	if rss.ITunesExt != nil && rss.ITunesExt.Image != "" {
@@ -292,6 +364,9 @@ func (t *DefaultRSSTranslator) translateFeedCategories(rss *rss.Feed) (categorie
 func (t *DefaultRSSTranslator) translateFeedItems(rss *rss.Feed) (items []*Item) {
 	items = []*Item{}
 	for _, i := range rss.Items {
+		if t.ctx.Err() != nil {
+			break
+		}
 		items = append(items, t.translateFeedItem(i))
 	}
 	return
@@ -321,6 +396,15 @@ func (t *DefaultRSSTranslator) translateItemContent(rssItem *rss.Item) (content
 	return rssItem.Content
 }
 
+// translateItemContentParts returns the content:encoded of the
+// item.  The description is a summary and stays in Description.
+func (t *DefaultRSSTranslator) translateItemContentParts(rssItem *rss.Item) (parts []*ContentPart) {
+	if rssItem.Content != "" {
+		parts = append(parts, &ContentPart{Type: ContentTypeHTML, Value: rssItem.Content})
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateItemLink(rssItem *rss.Item) (link string) {
 	return rssItem.Link
 }
@@ -342,7 +426,7 @@ func (t *DefaultRSSTranslator) translateItemUpdated(rssItem *rss.Item) (updated
 func (t *DefaultRSSTranslator) translateItemUpdatedParsed(rssItem *rss.Item) (updated *time.Time) {
 	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Date != nil {
 		updatedText := t.firstEntry(rssItem.DublinCoreExt.Date)
-		updatedDate, err := shared.ParseDate(updatedText)
+		updatedDate, err := t.dates.Parse(updatedText)
 		if err == nil {
 			updated = &updatedDate
 		}
@@ -364,7 +448,7 @@ func (t *DefaultRSSTranslator) translateItemPublishedParsed(rssItem *rss.Item) (
 		return rssItem.PubDateParsed
 	} else if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Date != nil {
 		pubDateText := t.firstEntry(rssItem.DublinCoreExt.Date)
-		pubDateParsed, err := shared.ParseDate(pubDateText)
+		pubDateParsed, err := t.dates.Parse(pubDateText)
 		if err == nil {
 			pubDate = &pubDateParsed
 		}
@@ -372,6 +456,20 @@ func (t *DefaultRSSTranslator) translateItemPublishedParsed(rssItem *rss.Item) (
 	return
 }
 
+func (t *DefaultRSSTranslator) translateItemDate(rssItem *rss.Item) (*time.Time, string) {
+	var dcDate *time.Time
+	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Date != nil {
+		date, err := t.dates.Parse(t.firstEntry(rssItem.DublinCoreExt.Date))
+		if err == nil {
+			dcDate = &date
+		}
+	}
+	return firstDate(
+		dateCandidate{rssItem.PubDateParsed, DateSourcePublished},
+		dateCandidate{dcDate, DateSourceDublinCore},
+	)
+}
+
 func (t *DefaultRSSTranslator) translateItemAuthor(rssItem *rss.Item) (author *Person) {
 	if rssItem.Author != "" {
 		name, address := shared.ParseNameAddress(rssItem.Author)
@@ -413,33 +511,6 @@ func (t *DefaultRSSTranslator) translateItemGUID(rssItem *rss.Item) (guid string
 	return
 }
 
-func (t *DefaultRSSTranslator) translateItemImage(rssItem *rss.Item) *Image {
-	if rssItem.ITunesExt != nil && rssItem.ITunesExt.Image != "" {
-		return &Image{URL: rssItem.ITunesExt.Image}
-	}
-	if media, ok := rssItem.Extensions["media"]; ok {
-		if content, ok := media["content"]; ok {
-			for _, c := range content {
-				if strings.Contains(c.Attrs["type"], "image") || strings.Contains(c.Attrs["medium"], "image") {
-					return &Image{URL: c.Attrs["url"]}
-				}
-			}
-		}
-	}
-	for _, enc := range rssItem.Enclosures {
-		if strings.HasPrefix(enc.Type, "image/") {
-			return &Image{URL: enc.URL}
-		}
-	}
-	if img := firstImageFromHtmlDocument(rssItem.Content); img != nil {
-		return img
-	}
-	if img := firstImageFromHtmlDocument(rssItem.Description); img != nil {
-		return img
-	}
-	return nil
-}
-
 func firstImageFromHtmlDocument(document string) *Image {
 	if doc, err := html.Parse(bytes.NewBufferString(document)); err == nil {
 		doc := goquery.NewDocumentFromNode(doc)
@@ -488,6 +559,7 @@ func (t *DefaultRSSTranslator) translateItemEnclosures(rssItem *rss.Item) (enclo
 			e.URL = enc.URL
 			e.Type = enc.Type
 			e.Length = enc.Length
+			e.Size = parseSize(enc.Length)
 			enclosures = append(enclosures, e)
 		}
 	}
@@ -499,6 +571,15 @@ func (t *DefaultRSSTranslator) translateItemEnclosures(rssItem *rss.Item) (enclo
 	return
 }
 
+func (t *DefaultRSSTranslator) translateItemSource(rssItem *rss.Item) (source *Source) {
+	if rssItem.Source != nil {
+		source = &Source{}
+		source.Title = rssItem.Source.Title
+		source.FeedURL = rssItem.Source.URL
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) extensionsForKeys(keys []string, extensions ext.Extensions) (matches []map[string][]ext.Extension) {
 	matches = []map[string][]ext.Extension{}
 
@@ -532,15 +613,26 @@ func (t *DefaultRSSTranslator) firstEntry(entries []string) (value string) {
 // This default implementation defines a set of
 // mapping rules between atom.Feed -> Feed
 // for each of the fields in Feed.
-type DefaultAtomTranslator struct{}
+type DefaultAtomTranslator struct {
+	TranslatorOptions
+}
 
 // Translate converts an Atom feed into the universal
 // feed type.
 func (t *DefaultAtomTranslator) Translate(feed interface{}) (*Feed, error) {
+	return t.TranslateWithContext(feed, context.Background())
+}
+
+// TranslateWithContext is like Translate but stops with the
+// error of the context once it's done.
+func (t *DefaultAtomTranslator) TranslateWithContext(feed interface{}, ctx context.Context) (*Feed, error) {
 	atom, found := feed.(*atom.Feed)
 	if !found {
 		return nil, fmt.Errorf("Feed did not match expected type of *atom.Feed")
 	}
+	c := *t
+	c.TranslatorOptions = t.forFeed(ctx)
+	t = &c
 
 	result := &Feed{}
 	result.Title = t.translateFeedTitle(atom)
@@ -557,10 +649,16 @@ func (t *DefaultAtomTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.Copyright = t.translateFeedCopyright(atom)
 	result.Categories = t.translateFeedCategories(atom)
 	result.Generator = t.translateFeedGenerator(atom)
+	result.DublinCoreExt = dublinCoreExt(atom.Extensions)
+	result.ITunesExt = itunesFeedExt(atom.Extensions)
 	result.Items = t.translateFeedItems(atom)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	result.Extensions = atom.Extensions
 	result.FeedVersion = atom.Version
 	result.FeedType = "atom"
+	fillItemDates(result, t.FirstSeen)
 	return result, nil
 }
 
@@ -569,6 +667,7 @@ func (t *DefaultAtomTranslator) translateFeedItem(entry *atom.Entry) (item *Item
 	item.Title = t.translateItemTitle(entry)
 	item.Description = t.translateItemDescription(entry)
 	item.Content = t.translateItemContent(entry)
+	item.ContentParts = t.translateItemContentParts(entry)
 	item.Link = t.translateItemLink(entry)
 	item.Links = t.translateItemLinks(entry)
 	item.Updated = t.translateItemUpdated(entry)
@@ -578,10 +677,17 @@ func (t *DefaultAtomTranslator) translateFeedItem(entry *atom.Entry) (item *Item
 	item.Author = t.translateItemAuthor(entry)
 	item.Authors = t.translateItemAuthors(entry)
 	item.GUID = t.translateItemGUID(entry)
-	item.Image = t.translateItemImage(entry)
 	item.Categories = t.translateItemCategories(entry)
 	item.Enclosures = t.translateItemEnclosures(entry)
+	item.Source = t.translateItemSource(entry)
+	item.Comments = t.translateItemComments(entry)
+	item.InReplyTo = itemInReplyTo(entry.Extensions)
 	item.Extensions = entry.Extensions
+	item.DublinCoreExt = dublinCoreExt(entry.Extensions)
+	item.ITunesExt = itunesItemExt(entry.Extensions)
+	item.Image = selectImage(item, nil, t.ImageSources)
+	t.Sanitizer.sanitizeItem(item)
+	item.DateParsed, item.DateSource = t.translateItemDate(entry)
 	return
 }
 
@@ -632,6 +738,7 @@ func (t *DefaultAtomTranslator) translateFeedAuthor(atom *atom.Feed) (author *Pe
 		feedAuthor := Person{}
 		feedAuthor.Name = a.Name
 		feedAuthor.Email = a.Email
+		feedAuthor.URI = a.URI
 		author = &feedAuthor
 	}
 	return
@@ -645,6 +752,7 @@ func (t *DefaultAtomTranslator) translateFeedAuthors(atom *atom.Feed) (authors [
 			authors = append(authors, &Person{
 				Name:  a.Name,
 				Email: a.Email,
+				URI:   a.URI,
 			})
 		}
 	}
@@ -706,6 +814,9 @@ func (t *DefaultAtomTranslator) translateFeedCategories(atom *atom.Feed) (catego
 func (t *DefaultAtomTranslator) translateFeedItems(atom *atom.Feed) (items []*Item) {
 	items = []*Item{}
 	for _, entry := range atom.Entries {
+		if t.ctx.Err() != nil {
+			break
+		}
 		items = append(items, t.translateFeedItem(entry))
 	}
 	return
@@ -726,6 +837,30 @@ func (t *DefaultAtomTranslator) translateItemContent(entry *atom.Entry) (content
 	return
 }
 
+// translateItemContentParts returns the content of the entry
+// with its type, which is text if none is given, or its src
+// and MIME type if it's out of line.
+func (t *DefaultAtomTranslator) translateItemContentParts(entry *atom.Entry) (parts []*ContentPart) {
+	if entry.Content == nil || (entry.Content.Value == "" && entry.Content.Src == "") {
+		return
+	}
+	part := &ContentPart{}
+	part.Type = strings.ToLower(strings.TrimSpace(entry.Content.Type))
+	switch part.Type {
+	case "":
+		if entry.Content.Src == "" {
+			part.Type = ContentTypeText
+		}
+	case "text/html":
+		part.Type = ContentTypeHTML
+	case "application/xhtml+xml":
+		part.Type = ContentTypeXHTML
+	}
+	part.Value = entry.Content.Value
+	part.Src = entry.Content.Src
+	return append(parts, part)
+}
+
 func (t *DefaultAtomTranslator) translateItemLink(entry *atom.Entry) (link string) {
 	l := t.firstLinkWithType("alternate", entry.Links)
 	if l != nil {
@@ -767,12 +902,30 @@ func (t *DefaultAtomTranslator) translateItemPublishedParsed(entry *atom.Entry)
 	return
 }
 
+func (t *DefaultAtomTranslator) translateItemDate(entry *atom.Entry) (*time.Time, string) {
+	var dcDate *time.Time
+	if dc, ok := entry.Extensions["dc"]; ok {
+		if values := dc["date"]; len(values) > 0 {
+			date, err := t.dates.Parse(values[0].Value)
+			if err == nil {
+				dcDate = &date
+			}
+		}
+	}
+	return firstDate(
+		dateCandidate{entry.PublishedParsed, DateSourcePublished},
+		dateCandidate{entry.UpdatedParsed, DateSourceUpdated},
+		dateCandidate{dcDate, DateSourceDublinCore},
+	)
+}
+
 func (t *DefaultAtomTranslator) translateItemAuthor(entry *atom.Entry) (author *Person) {
 	a := t.firstPerson(entry.Authors)
 	if a != nil {
 		author = &Person{}
 		author.Name = a.Name
 		author.Email = a.Email
+		author.URI = a.URI
 	}
 	return
 }
@@ -784,6 +937,7 @@ func (t *DefaultAtomTranslator) translateItemAuthors(entry *atom.Entry) (authors
 			authors = append(authors, &Person{
 				Name:  a.Name,
 				Email: a.Email,
+				URI:   a.URI,
 			})
 		}
 	}
@@ -794,10 +948,6 @@ func (t *DefaultAtomTranslator) translateItemGUID(entry *atom.Entry) (guid strin
 	return entry.ID
 }
 
-func (t *DefaultAtomTranslator) translateItemImage(entry *atom.Entry) (image *Image) {
-	return nil
-}
-
 func (t *DefaultAtomTranslator) translateItemCategories(entry *atom.Entry) (categories []string) {
 	if entry.Categories != nil {
 		categories = []string{}
@@ -820,7 +970,9 @@ func (t *DefaultAtomTranslator) translateItemEnclosures(entry *atom.Entry) (encl
 				enclosure := &Enclosure{}
 				enclosure.URL = e.Href
 				enclosure.Length = e.Length
+				enclosure.Size = parseSize(e.Length)
 				enclosure.Type = e.Type
+				enclosure.Title = e.Title
 				enclosures = append(enclosures, enclosure)
 			}
 		}
@@ -832,6 +984,39 @@ func (t *DefaultAtomTranslator) translateItemEnclosures(entry *atom.Entry) (encl
 	return
 }
 
+func (t *DefaultAtomTranslator) translateItemSource(entry *atom.Entry) (source *Source) {
+	if entry.Source != nil {
+		source = &Source{}
+		source.Title = entry.Source.Title
+		if l := t.firstLinkWithType("alternate", entry.Source.Links); l != nil {
+			source.Link = l.Href
+		}
+		if l := t.firstLinkWithType("self", entry.Source.Links); l != nil {
+			source.FeedURL = l.Href
+		}
+	}
+	return
+}
+
+// translateItemComments takes the comments page and feed from
+// the entry's "replies" links, telling them apart by type.
+func (t *DefaultAtomTranslator) translateItemComments(entry *atom.Entry) *Comments {
+	var page, feed string
+	for _, l := range entry.Links {
+		if l.Rel != "replies" {
+			continue
+		}
+		if strings.Contains(l.Type, "xml") {
+			if feed == "" {
+				feed = l.Href
+			}
+		} else if page == "" {
+			page = l.Href
+		}
+	}
+	return itemComments(page, feed, entry.Extensions)
+}
+
 func (t *DefaultAtomTranslator) firstLinkWithType(linkType string, links []*atom.Link) *atom.Link {
 	if links == nil {
 		return nil
@@ -860,15 +1045,26 @@ func (t *DefaultAtomTranslator) firstPerson(persons []*atom.Person) (person *ato
 // This default implementation defines a set of
 // mapping rules between json.Feed -> Feed
 // for each of the fields in Feed.
-type DefaultJSONTranslator struct{}
+type DefaultJSONTranslator struct {
+	TranslatorOptions
+}
 
 // Translate converts an JSON feed into the universal
 // feed type.
 func (t *DefaultJSONTranslator) Translate(feed interface{}) (*Feed, error) {
+	return t.TranslateWithContext(feed, context.Background())
+}
+
+// TranslateWithContext is like Translate but stops with the
+// error of the context once it's done.
+func (t *DefaultJSONTranslator) TranslateWithContext(feed interface{}, ctx context.Context) (*Feed, error) {
 	json, found := feed.(*json.Feed)
 	if !found {
 		return nil, fmt.Errorf("Feed did not match expected type of *json.Feed")
 	}
+	c := *t
+	c.TranslatorOptions = t.forFeed(ctx)
+	t = &c
 
 	result := &Feed{}
 	result.FeedVersion = json.Version
@@ -876,23 +1072,30 @@ func (t *DefaultJSONTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.Link = t.translateFeedLink(json)
 	result.FeedLink = t.translateFeedFeedLink(json)
 	result.Links = t.translateFeedLinks(json)
+	result.NextLink = t.translateFeedNextLink(json)
+	result.Hubs = t.translateFeedHubs(json)
 	result.Description = t.translateFeedDescription(json)
 	result.Image = t.translateFeedImage(json)
+	result.Favicon = t.translateFeedFavicon(json)
 	result.Author = t.translateFeedAuthor(json)
 	result.Authors = t.translateFeedAuthors(json)
 	result.Language = t.translateFeedLanguage(json)
+	result.Expired = json.Expired
+	result.Extensions = t.translateExtensions(json.Extensions)
+	result.DublinCoreExt = dublinCoreExt(result.Extensions)
+	result.ITunesExt = itunesFeedExt(result.Extensions)
 	result.Items = t.translateFeedItems(json)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	result.Updated = t.translateFeedUpdated(json)
 	result.UpdatedParsed = t.translateFeedUpdatedParsed(json)
 	result.Published = t.translateFeedPublished(json)
 	result.PublishedParsed = t.translateFeedPublishedParsed(json)
 	result.FeedType = "json"
-	// TODO UserComment is missing in global Feed
-	// TODO NextURL is missing in global Feed
-	// TODO Favicon is missing in global Feed
-	// TODO Exipred is missing in global Feed
-	// TODO Hubs is not supported in json.Feed
-	// TODO Extensions is not supported in json.Feed
+	fillItemDates(result, t.FirstSeen)
+	// UserComment is meant for people reading the raw
+	// JSON and is ignored, as the spec asks readers to.
 	return result, nil
 }
 
@@ -903,8 +1106,8 @@ func (t *DefaultJSONTranslator) translateFeedItem(jsonItem *json.Item) (item *It
 	item.Links = t.translateItemLinks(jsonItem)
 	item.Title = t.translateItemTitle(jsonItem)
 	item.Content = t.translateItemContent(jsonItem)
+	item.ContentParts = t.translateItemContentParts(jsonItem)
 	item.Description = t.translateItemDescription(jsonItem)
-	item.Image = t.translateItemImage(jsonItem)
 	item.Published = t.translateItemPublished(jsonItem)
 	item.PublishedParsed = t.translateItemPublishedParsed(jsonItem)
 	item.Updated = t.translateItemUpdated(jsonItem)
@@ -913,8 +1116,13 @@ func (t *DefaultJSONTranslator) translateFeedItem(jsonItem *json.Item) (item *It
 	item.Authors = t.translateItemAuthors(jsonItem)
 	item.Categories = t.translateItemCategories(jsonItem)
 	item.Enclosures = t.translateItemEnclosures(jsonItem)
+	item.Extensions = t.translateExtensions(jsonItem.Extensions)
+	item.DublinCoreExt = dublinCoreExt(item.Extensions)
+	item.ITunesExt = itunesItemExt(item.Extensions)
+	item.Image = selectImage(item, t.translateItemImage(jsonItem), t.ImageSources)
+	t.Sanitizer.sanitizeItem(item)
+	item.DateParsed, item.DateSource = t.translateItemDate(item)
 	// TODO ExternalURL is missing in global Feed
-	// TODO BannerImage is missing in global Feed
 	return
 }
 
@@ -953,6 +1161,20 @@ func (t *DefaultJSONTranslator) translateFeedLinks(json *json.Feed) (links []str
 	return
 }
 
+func (t *DefaultJSONTranslator) translateFeedNextLink(json *json.Feed) (link string) {
+	return json.NextURL
+}
+
+func (t *DefaultJSONTranslator) translateFeedHubs(json *json.Feed) (hubs []*Hub) {
+	for _, h := range json.Hubs {
+		if h == nil || h.URL == "" {
+			continue
+		}
+		hubs = append(hubs, &Hub{Type: h.Type, URL: h.URL})
+	}
+	return
+}
+
 func (t *DefaultJSONTranslator) translateFeedUpdated(json *json.Feed) (updated string) {
 	if len(json.Items) > 0 {
 		updated = json.Items[0].DateModified
@@ -962,7 +1184,7 @@ func (t *DefaultJSONTranslator) translateFeedUpdated(json *json.Feed) (updated s
 
 func (t *DefaultJSONTranslator) translateFeedUpdatedParsed(json *json.Feed) (updated *time.Time) {
 	if len(json.Items) > 0 {
-		updateTime, err := shared.ParseDate(json.Items[0].DateModified)
+		updateTime, err := t.dates.Parse(json.Items[0].DateModified)
 		if err == nil {
 			updated = &updateTime
 		}
@@ -979,7 +1201,7 @@ func (t *DefaultJSONTranslator) translateFeedPublished(json *json.Feed) (publish
 
 func (t *DefaultJSONTranslator) translateFeedPublishedParsed(json *json.Feed) (published *time.Time) {
 	if len(json.Items) > 0 {
-		publishTime, err := shared.ParseDate(json.Items[0].DatePublished)
+		publishTime, err := t.dates.Parse(json.Items[0].DatePublished)
 		if err == nil {
 			published = &publishTime
 		}
@@ -993,9 +1215,9 @@ func (t *DefaultJSONTranslator) translateFeedAuthor(json *json.Feed) (author *Pe
 		author = &Person{}
 		author.Name = name
 		author.Email = address
+		author.URI = json.Author.URL
+		author.Avatar = json.Author.Avatar
 	}
-	// Author.URL is missing in global feed
-	// Author.Avatar is missing in global feed
 	return
 }
 
@@ -1007,14 +1229,14 @@ func (t *DefaultJSONTranslator) translateFeedAuthors(json *json.Feed) (authors [
 			author := &Person{}
 			author.Name = name
 			author.Email = address
+			author.URI = a.URL
+			author.Avatar = a.Avatar
 
 			authors = append(authors, author)
 		}
 	} else if author := t.translateFeedAuthor(json); author != nil {
 		authors = []*Person{author}
 	}
-	// Author.URL is missing in global feed
-	// Author.Avatar is missing in global feed
 	return
 }
 
@@ -1033,9 +1255,73 @@ func (t *DefaultJSONTranslator) translateFeedImage(json *json.Feed) (image *Imag
 	return
 }
 
+func (t *DefaultJSONTranslator) translateFeedFavicon(json *json.Feed) (image *Image) {
+	if json.Favicon != "" {
+		image = &Image{}
+		image.URL = json.Favicon
+	}
+	return
+}
+
+// translateExtensions maps the custom objects of a JSON feed or
+// item to extensions of the same shape as XML extensions.  The
+// name of an object without its underscore is the namespace,
+// and its members are the elements: nested objects become
+// children, arrays repeat the element and other values are
+// its text.  Extensions which aren't objects are skipped.
+func (t *DefaultJSONTranslator) translateExtensions(jsonExt json.Extensions) (extensions ext.Extensions) {
+	for name, value := range jsonExt {
+		members, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if extensions == nil {
+			extensions = ext.Extensions{}
+		}
+		extensions[strings.TrimPrefix(name, "_")] = jsonExtensionChildren(members)
+	}
+	return
+}
+
+func jsonExtensionChildren(members map[string]interface{}) map[string][]ext.Extension {
+	children := map[string][]ext.Extension{}
+	for name, value := range members {
+		children[name] = append(children[name], jsonExtension(name, value)...)
+	}
+	return children
+}
+
+func jsonExtension(name string, value interface{}) []ext.Extension {
+	e := ext.Extension{
+		Name:     name,
+		Attrs:    map[string]string{},
+		Children: map[string][]ext.Extension{},
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		var values []ext.Extension
+		for _, elem := range v {
+			values = append(values, jsonExtension(name, elem)...)
+		}
+		return values
+	case map[string]interface{}:
+		e.Children = jsonExtensionChildren(v)
+	case string:
+		e.Value = v
+	case float64:
+		e.Value = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		e.Value = strconv.FormatBool(v)
+	}
+	return []ext.Extension{e}
+}
+
 func (t *DefaultJSONTranslator) translateFeedItems(json *json.Feed) (items []*Item) {
 	items = []*Item{}
 	for _, i := range json.Items {
+		if t.ctx.Err() != nil {
+			break
+		}
 		items = append(items, t.translateFeedItem(i))
 	}
 	return
@@ -1064,6 +1350,16 @@ func (t *DefaultJSONTranslator) translateItemContent(jsonItem *json.Item) (conte
 	return
 }
 
+func (t *DefaultJSONTranslator) translateItemContentParts(jsonItem *json.Item) (parts []*ContentPart) {
+	if jsonItem.ContentHTML != "" {
+		parts = append(parts, &ContentPart{Type: ContentTypeHTML, Value: jsonItem.ContentHTML})
+	}
+	if jsonItem.ContentText != "" {
+		parts = append(parts, &ContentPart{Type: ContentTypeText, Value: jsonItem.ContentText})
+	}
+	return
+}
+
 func (t *DefaultJSONTranslator) translateItemLink(jsonItem *json.Item) (link string) {
 	return jsonItem.URL
 }
@@ -1087,7 +1383,7 @@ func (t *DefaultJSONTranslator) translateItemUpdated(jsonItem *json.Item) (updat
 
 func (t *DefaultJSONTranslator) translateItemUpdatedParsed(jsonItem *json.Item) (updated *time.Time) {
 	if jsonItem.DateModified != "" {
-		updatedTime, err := shared.ParseDate(jsonItem.DateModified)
+		updatedTime, err := t.dates.Parse(jsonItem.DateModified)
 		if err == nil {
 			updated = &updatedTime
 		}
@@ -1104,7 +1400,7 @@ func (t *DefaultJSONTranslator) translateItemPublished(jsonItem *json.Item) (pub
 
 func (t *DefaultJSONTranslator) translateItemPublishedParsed(jsonItem *json.Item) (pubDate *time.Time) {
 	if jsonItem.DatePublished != "" {
-		publishTime, err := shared.ParseDate(jsonItem.DatePublished)
+		publishTime, err := t.dates.Parse(jsonItem.DatePublished)
 		if err == nil {
 			pubDate = &publishTime
 		}
@@ -1112,15 +1408,30 @@ func (t *DefaultJSONTranslator) translateItemPublishedParsed(jsonItem *json.Item
 	return
 }
 
+func (t *DefaultJSONTranslator) translateItemDate(item *Item) (*time.Time, string) {
+	var dcDate *time.Time
+	if item.DublinCoreExt != nil && len(item.DublinCoreExt.Date) > 0 {
+		date, err := t.dates.Parse(item.DublinCoreExt.Date[0])
+		if err == nil {
+			dcDate = &date
+		}
+	}
+	return firstDate(
+		dateCandidate{item.PublishedParsed, DateSourcePublished},
+		dateCandidate{item.UpdatedParsed, DateSourceUpdated},
+		dateCandidate{dcDate, DateSourceDublinCore},
+	)
+}
+
 func (t *DefaultJSONTranslator) translateItemAuthor(jsonItem *json.Item) (author *Person) {
 	if jsonItem.Author != nil {
 		name, address := shared.ParseNameAddress(jsonItem.Author.Name)
 		author = &Person{}
 		author.Name = name
 		author.Email = address
+		author.URI = jsonItem.Author.URL
+		author.Avatar = jsonItem.Author.Avatar
 	}
-	// Author.URL is missing in global feed
-	// Author.Avatar is missing in global feed
 	return
 }
 
@@ -1132,14 +1443,14 @@ func (t *DefaultJSONTranslator) translateItemAuthors(jsonItem *json.Item) (autho
 			author := &Person{}
 			author.Name = name
 			author.Email = address
+			author.URI = a.URL
+			author.Avatar = a.Avatar
 
 			authors = append(authors, author)
 		}
 	} else if author := t.translateItemAuthor(jsonItem); author != nil {
 		authors = []*Person{author}
 	}
-	// Author.URL is missing in global feed
-	// Author.Avatar is missing in global feed
 	return
 }
 
@@ -1174,11 +1485,566 @@ func (t *DefaultJSONTranslator) translateItemEnclosures(jsonItem *json.Item) (en
 			e := &Enclosure{}
 			e.URL = attachment.URL
 			e.Type = attachment.MimeType
-			e.Length = fmt.Sprintf("%d", attachment.DurationInSeconds)
-			// Title is not defined in global enclosure
-			// SizeInBytes is not defined in global enclosure
+			e.Title = attachment.Title
+			if attachment.SizeInBytes > 0 {
+				e.Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+				e.Size = attachment.SizeInBytes
+			}
+			e.Duration = time.Duration(attachment.DurationInSeconds) * time.Second
 			enclosures = append(enclosures, e)
 		}
 	}
 	return
 }
+
+// DefaultHFeedTranslator converts an hfeed.Feed struct
+// into the generic Feed struct.
+//
+// This default implementation defines a set of
+// mapping rules between hfeed.Feed -> Feed
+// for each of the fields in Feed.
+type DefaultHFeedTranslator struct {
+	TranslatorOptions
+}
+
+// Translate converts an h-feed into the universal
+// feed type.
+func (t *DefaultHFeedTranslator) Translate(feed interface{}) (*Feed, error) {
+	return t.TranslateWithContext(feed, context.Background())
+}
+
+// TranslateWithContext is like Translate but stops with the
+// error of the context once it's done.
+func (t *DefaultHFeedTranslator) TranslateWithContext(feed interface{}, ctx context.Context) (*Feed, error) {
+	hfeed, found := feed.(*hfeed.Feed)
+	if !found {
+		return nil, fmt.Errorf("Feed did not match expected type of *hfeed.Feed")
+	}
+	c := *t
+	c.TranslatorOptions = t.forFeed(ctx)
+	t = &c
+
+	result := &Feed{}
+	result.Title = hfeed.Name
+	result.Description = hfeed.Summary
+	result.Link = hfeed.URL
+	result.Links = t.translateFeedLinks(hfeed)
+	result.Image = t.translateFeedImage(hfeed)
+	result.Authors = t.translatePersons(hfeed.Authors)
+	result.Author = t.firstPerson(result.Authors)
+	result.Language = hfeed.Language
+	result.Items = t.translateFeedItems(hfeed)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result.FeedType = "hfeed"
+	fillItemDates(result, t.FirstSeen)
+	return result, nil
+}
+
+func (t *DefaultHFeedTranslator) translateFeedItems(hfeed *hfeed.Feed) (items []*Item) {
+	items = []*Item{}
+	for _, entry := range hfeed.Entries {
+		if t.ctx.Err() != nil {
+			break
+		}
+		item := t.translateFeedItem(entry)
+		// Entries without authors of their
+		// own are by the author of the feed.
+		if len(item.Authors) == 0 {
+			item.Authors = t.translatePersons(hfeed.Authors)
+			item.Author = t.firstPerson(item.Authors)
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateFeedItem(entry *hfeed.Entry) (item *Item) {
+	item = &Item{}
+	item.Title = t.translateItemTitle(entry)
+	item.Description = entry.Summary
+	item.Content = t.translateItemContent(entry)
+	item.ContentParts = t.translateItemContentParts(entry)
+	item.Link = entry.URL
+	item.Links = t.translateItemLinks(entry)
+	item.GUID = t.translateItemGUID(entry)
+	item.Published = entry.Published
+	item.PublishedParsed = t.parseDate(entry.Published)
+	item.Updated = entry.Updated
+	item.UpdatedParsed = t.parseDate(entry.Updated)
+	item.Authors = t.translatePersons(entry.Authors)
+	item.Author = t.firstPerson(item.Authors)
+	item.Categories = entry.Categories
+	item.Image = selectImage(item, t.translateItemImage(entry), t.ImageSources)
+	t.Sanitizer.sanitizeItem(item)
+	item.DateParsed, item.DateSource = firstDate(
+		dateCandidate{item.PublishedParsed, DateSourcePublished},
+		dateCandidate{item.UpdatedParsed, DateSourceUpdated},
+	)
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateFeedLinks(hfeed *hfeed.Feed) (links []string) {
+	if hfeed.URL != "" {
+		links = append(links, hfeed.URL)
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateFeedImage(hfeed *hfeed.Feed) (image *Image) {
+	if hfeed.Photo != "" {
+		image = &Image{}
+		image.URL = hfeed.Photo
+		image.Title = hfeed.Name
+	}
+	return
+}
+
+// translateItemTitle returns the name of an entry unless it is
+// the text of its content, which is implied for notes without
+// a title.
+func (t *DefaultHFeedTranslator) translateItemTitle(entry *hfeed.Entry) (title string) {
+	title = entry.Name
+	if entry.Content != nil && strings.Join(strings.Fields(entry.Content.Value), " ") == title {
+		title = ""
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateItemContent(entry *hfeed.Entry) (content string) {
+	if entry.Content != nil {
+		content = entry.Content.HTML
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateItemContentParts(entry *hfeed.Entry) (parts []*ContentPart) {
+	if entry.Content == nil {
+		return
+	}
+	if entry.Content.HTML != "" {
+		parts = append(parts, &ContentPart{Type: ContentTypeHTML, Value: entry.Content.HTML})
+	}
+	if entry.Content.Value != "" {
+		parts = append(parts, &ContentPart{Type: ContentTypeText, Value: entry.Content.Value})
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateItemLinks(entry *hfeed.Entry) (links []string) {
+	if entry.URL != "" {
+		links = append(links, entry.URL)
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateItemGUID(entry *hfeed.Entry) (guid string) {
+	if entry.UID != "" {
+		guid = entry.UID
+	} else {
+		guid = entry.URL
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translateItemImage(entry *hfeed.Entry) (image *Image) {
+	if len(entry.Photos) > 0 {
+		image = &Image{}
+		image.URL = entry.Photos[0]
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) translatePersons(cards []*hfeed.Card) (persons []*Person) {
+	for _, card := range cards {
+		person := &Person{}
+		person.Name = card.Name
+		person.Email = card.Email
+		person.URI = card.URL
+		person.Avatar = card.Photo
+		persons = append(persons, person)
+	}
+	return
+}
+
+func (t *DefaultHFeedTranslator) firstPerson(persons []*Person) *Person {
+	if len(persons) == 0 {
+		return nil
+	}
+	return persons[0]
+}
+
+func (t *DefaultHFeedTranslator) parseDate(date string) (parsed *time.Time) {
+	if date != "" {
+		if d, err := t.dates.Parse(date); err == nil {
+			parsed = &d
+		}
+	}
+	return
+}
+
+// DefaultActivityStreamsTranslator converts an
+// activitystreams.Collection struct into the generic
+// Feed struct.
+//
+// This default implementation defines a set of
+// mapping rules between activitystreams.Collection -> Feed
+// for each of the fields in Feed.  Only Create activities
+// and objects which aren't wrapped in an activity become
+// items.
+type DefaultActivityStreamsTranslator struct {
+	TranslatorOptions
+}
+
+// Translate converts an ActivityStreams collection
+// into the universal feed type.
+func (t *DefaultActivityStreamsTranslator) Translate(feed interface{}) (*Feed, error) {
+	return t.TranslateWithContext(feed, context.Background())
+}
+
+// TranslateWithContext is like Translate but stops with the
+// error of the context once it's done.
+func (t *DefaultActivityStreamsTranslator) TranslateWithContext(feed interface{}, ctx context.Context) (*Feed, error) {
+	collection, found := feed.(*activitystreams.Collection)
+	if !found {
+		return nil, fmt.Errorf("Feed did not match expected type of *activitystreams.Collection")
+	}
+	c := *t
+	c.TranslatorOptions = t.forFeed(ctx)
+	t = &c
+
+	result := &Feed{}
+	result.Title = collection.Name
+	result.Description = collection.Summary
+	result.FeedLink = t.translateFeedFeedLink(collection)
+	result.NextLink = t.translateFeedNextLink(collection)
+	result.Items = t.translateFeedItems(collection)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result.FeedType = "activitystreams"
+	result.FeedVersion = "2.0"
+	fillItemDates(result, t.FirstSeen)
+	return result, nil
+}
+
+// translateFeedFeedLink returns the id of the collection, rather
+// than that of the page when the collection is paged.
+func (t *DefaultActivityStreamsTranslator) translateFeedFeedLink(collection *activitystreams.Collection) (link string) {
+	if collection.PartOf != "" {
+		link = collection.PartOf
+	} else {
+		link = collection.ID
+	}
+	return
+}
+
+// translateFeedNextLink returns the next page of a collection,
+// or its first page if the collection itself has no items.
+func (t *DefaultActivityStreamsTranslator) translateFeedNextLink(collection *activitystreams.Collection) (link string) {
+	if collection.Next != "" {
+		link = collection.Next
+	} else if len(collection.Items) == 0 && collection.First != collection.ID {
+		link = collection.First
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateFeedItems(collection *activitystreams.Collection) (items []*Item) {
+	items = []*Item{}
+	for _, activity := range collection.Items {
+		if t.ctx.Err() != nil {
+			break
+		}
+		if activity.Object == nil || activity.Object.Type == "Tombstone" {
+			continue
+		}
+		if activity.Type != "" && activity.Type != "Create" {
+			continue
+		}
+		// Objects only given by their id have nothing to show
+		if activity.Object.Content == "" && activity.Object.Name == "" && activity.Object.Summary == "" && len(activity.Object.Attachments) == 0 {
+			continue
+		}
+		items = append(items, t.translateFeedItem(activity))
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateFeedItem(activity *activitystreams.Activity) (item *Item) {
+	object := activity.Object
+	item = &Item{}
+	item.Title = object.Name
+	item.Description = object.Summary
+	item.Content = object.Content
+	item.ContentParts = t.translateItemContentParts(object)
+	item.Link = t.translateItemLink(object)
+	item.Links = t.translateItemLinks(object)
+	item.GUID = object.ID
+	item.Published = t.translateItemPublished(activity)
+	item.PublishedParsed = t.parseDate(item.Published)
+	item.Updated = object.Updated
+	item.UpdatedParsed = t.parseDate(object.Updated)
+	item.Author = t.translateItemAuthor(activity)
+	if item.Author != nil {
+		item.Authors = []*Person{item.Author}
+	}
+	item.Categories = t.translateItemCategories(object)
+	item.Enclosures = t.translateItemEnclosures(object)
+	item.InReplyTo = t.translateItemInReplyTo(object)
+	item.Image = selectImage(item, nil, t.ImageSources)
+	t.Sanitizer.sanitizeItem(item)
+	item.DateParsed, item.DateSource = firstDate(
+		dateCandidate{item.PublishedParsed, DateSourcePublished},
+		dateCandidate{item.UpdatedParsed, DateSourceUpdated},
+	)
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateItemContentParts(object *activitystreams.Object) (parts []*ContentPart) {
+	if object.Content == "" {
+		return
+	}
+	// ActivityStreams content is HTML unless
+	// the object says otherwise.
+	contentType := ContentTypeHTML
+	switch object.MediaType {
+	case "", "text/html":
+	case "text/plain":
+		contentType = ContentTypeText
+	default:
+		contentType = object.MediaType
+	}
+	parts = append(parts, &ContentPart{Type: contentType, Value: object.Content})
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateItemLink(object *activitystreams.Object) (link string) {
+	if object.URL != "" {
+		link = object.URL
+	} else {
+		link = object.ID
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateItemLinks(object *activitystreams.Object) (links []string) {
+	if object.URL != "" {
+		links = append(links, object.URL)
+	}
+	if object.ID != "" && object.ID != object.URL {
+		links = append(links, object.ID)
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateItemPublished(activity *activitystreams.Activity) (published string) {
+	if activity.Object.Published != "" {
+		published = activity.Object.Published
+	} else {
+		published = activity.Published
+	}
+	return
+}
+
+// translateItemAuthor returns the author of the object,
+// or failing that the actor of the activity.
+func (t *DefaultActivityStreamsTranslator) translateItemAuthor(activity *activitystreams.Activity) (author *Person) {
+	actor := activity.Object.AttributedTo
+	if actor == nil || (*actor == activitystreams.Actor{ID: actor.ID} && activity.Actor != nil) {
+		// The actor of the activity may be
+		// more than the id the object gives.
+		actor = activity.Actor
+	}
+	if actor == nil {
+		return
+	}
+
+	author = &Person{}
+	author.Name = actor.Name
+	if author.Name == "" {
+		author.Name = actor.PreferredUsername
+	}
+	if actor.URL != "" {
+		author.URI = actor.URL
+	} else {
+		author.URI = actor.ID
+	}
+	author.Avatar = actor.Icon
+	return
+}
+
+// translateItemCategories returns the names of
+// the hashtags of an object, without the #.
+func (t *DefaultActivityStreamsTranslator) translateItemCategories(object *activitystreams.Object) (categories []string) {
+	for _, tag := range object.Tags {
+		if tag.Type == "Hashtag" && tag.Name != "" {
+			categories = append(categories, strings.TrimPrefix(tag.Name, "#"))
+		}
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateItemEnclosures(object *activitystreams.Object) (enclosures []*Enclosure) {
+	for _, attachment := range object.Attachments {
+		if attachment.URL == "" {
+			continue
+		}
+		e := &Enclosure{}
+		e.URL = attachment.URL
+		e.Type = attachment.MediaType
+		e.Title = attachment.Name
+		enclosures = append(enclosures, e)
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) translateItemInReplyTo(object *activitystreams.Object) (replies []*InReplyTo) {
+	if object.InReplyTo != "" {
+		replies = append(replies, &InReplyTo{Ref: object.InReplyTo, Href: object.InReplyTo})
+	}
+	return
+}
+
+func (t *DefaultActivityStreamsTranslator) parseDate(date string) (parsed *time.Time) {
+	if date != "" {
+		if d, err := t.dates.Parse(date); err == nil {
+			parsed = &d
+		}
+	}
+	return
+}
+
+type dateCandidate struct {
+	date   *time.Time
+	source string
+}
+
+// firstDate returns a copy of the first date which
+// is set along with the source of that date.
+func firstDate(candidates ...dateCandidate) (*time.Time, string) {
+	for _, c := range candidates {
+		if c.date != nil {
+			date := *c.date
+			return &date, c.source
+		}
+	}
+	return nil, ""
+}
+
+// fillItemDates dates the items which have no date of their
+// own with the date of the feed, or failing that the time they
+// were first seen.
+func fillItemDates(feed *Feed, firstSeen func(item *Item) time.Time) {
+	feedDate, _ := firstDate(
+		dateCandidate{feed.PublishedParsed, DateSourceFeed},
+		dateCandidate{feed.UpdatedParsed, DateSourceFeed},
+	)
+	for _, item := range feed.Items {
+		if item.DateParsed != nil {
+			continue
+		}
+		if feedDate != nil {
+			item.DateParsed, item.DateSource = firstDate(dateCandidate{feedDate, DateSourceFeed})
+		} else if firstSeen != nil {
+			if seen := firstSeen(item); !seen.IsZero() {
+				item.DateParsed, item.DateSource = &seen, DateSourceFirstSeen
+			}
+		}
+	}
+}
+
+// itemComments returns the comments of an item given the
+// page and feed of its comments, if known, and its extensions.
+// The feed is otherwise taken from wfw:commentRss, and the
+// count from slash:comments or thr:total.
+func itemComments(page, feed string, extensions ext.Extensions) *Comments {
+	if feed == "" {
+		feed = extensionValue(extensions, "wfw", "commentRss")
+	}
+	var count *int
+	for _, total := range []string{
+		extensionValue(extensions, "slash", "comments"),
+		extensionValue(extensions, "thr", "total"),
+	} {
+		if n, err := strconv.Atoi(strings.TrimSpace(total)); err == nil && n >= 0 {
+			count = &n
+			break
+		}
+	}
+	if page == "" && feed == "" && count == nil {
+		return nil
+	}
+	return &Comments{Link: page, FeedURL: strings.TrimSpace(feed), Count: count}
+}
+
+// itemInReplyTo returns the thr:in-reply-to
+// elements among the extensions of an item.
+func itemInReplyTo(extensions ext.Extensions) (replies []*InReplyTo) {
+	for _, e := range extensions["thr"]["in-reply-to"] {
+		replies = append(replies, &InReplyTo{
+			Ref:    e.Attrs["ref"],
+			Href:   e.Attrs["href"],
+			Type:   e.Attrs["type"],
+			Source: e.Attrs["source"],
+		})
+	}
+	return
+}
+
+// extensionValue returns the value of the first
+// extension element with the prefix and name.
+func extensionValue(extensions ext.Extensions, prefix, name string) string {
+	if values := extensions[prefix][name]; len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+// parseSize returns the number of bytes given by the
+// length of an enclosure, or zero if it isn't valid.
+func parseSize(length string) int64 {
+	size, err := strconv.ParseInt(strings.TrimSpace(length), 10, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// parseDimension returns the number of pixels given by
+// the width or height of an image, or zero if it isn't valid.
+func parseDimension(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// dublinCoreExt builds the Dublin Core extension of a feed or
+// item from its generic extensions, as the RSS parser does.
+func dublinCoreExt(extensions ext.Extensions) *ext.DublinCoreExtension {
+	if dc, ok := extensions["dc"]; ok {
+		return ext.NewDublinCoreExtension(dc)
+	}
+	return nil
+}
+
+// itunesFeedExt builds the iTunes extension of a feed
+// from its generic extensions.
+func itunesFeedExt(extensions ext.Extensions) *ext.ITunesFeedExtension {
+	if itunes, ok := extensions["itunes"]; ok {
+		return ext.NewITunesFeedExtension(itunes)
+	}
+	return nil
+}
+
+// itunesItemExt builds the iTunes extension of an
+// item from its generic extensions.
+func itunesItemExt(extensions ext.Extensions) *ext.ITunesItemExtension {
+	if itunes, ok := extensions["itunes"]; ok {
+		return ext.NewITunesItemExtension(itunes)
+	}
+	return nil
+}
@@ -1,15 +1,19 @@
 package gofeed_test
 
 import (
+	"context"
 	jsonEncoding "encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/activitystreams"
 	"github.com/mmcdole/gofeed/atom"
+	"github.com/mmcdole/gofeed/hfeed"
 	"github.com/mmcdole/gofeed/json"
 	"github.com/mmcdole/gofeed/rss"
 	"github.com/stretchr/testify/assert"
@@ -57,6 +61,60 @@ func TestDefaultRSSTranslator_Translate_WrongType(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestDefaultRSSTranslator_TranslateWithContext(t *testing.T) {
+	rssFeed := &rss.Feed{Title: "Title", Items: []*rss.Item{{Title: "Item"}}}
+	translator := &gofeed.DefaultRSSTranslator{}
+
+	feed, err := translator.TranslateWithContext(rssFeed, context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, feed.Items, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	feed, err = translator.TranslateWithContext(rssFeed, ctx)
+	assert.Nil(t, feed)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestDefaultRSSTranslator_Translate_ItemDates(t *testing.T) {
+	feedData := `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<item><title>pubDate</title><pubDate>Thu, 01 Jan 2004 19:48:21 GMT</pubDate></item>
+<item><title>dc:date</title><dc:date>2004-01-02T10:00:00Z</dc:date></item>
+<item><title>undated</title></item>
+</channel></rss>`
+	firstSeen := time.Date(2004, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	fp := &rss.Parser{}
+	rssFeed, err := fp.Parse(strings.NewReader(feedData))
+	assert.Nil(t, err)
+
+	translator := &gofeed.DefaultRSSTranslator{TranslatorOptions: gofeed.TranslatorOptions{
+		FirstSeen: func(item *gofeed.Item) time.Time { return firstSeen },
+	}}
+	feed, err := translator.Translate(rssFeed)
+	assert.Nil(t, err)
+	assert.Equal(t, gofeed.DateSourcePublished, feed.Items[0].DateSource)
+	assert.Equal(t, time.Date(2004, time.January, 1, 19, 48, 21, 0, time.UTC), *feed.Items[0].DateParsed)
+	assert.Equal(t, gofeed.DateSourceDublinCore, feed.Items[1].DateSource)
+	assert.Equal(t, 2, feed.Items[1].DateParsed.Day())
+	assert.Equal(t, gofeed.DateSourceFirstSeen, feed.Items[2].DateSource)
+	assert.Equal(t, firstSeen, *feed.Items[2].DateParsed)
+
+	// The feed's own date comes before the first seen time
+	rssFeed.PubDateParsed = &[]time.Time{time.Date(2004, time.January, 3, 0, 0, 0, 0, time.UTC)}[0]
+	feed, err = translator.Translate(rssFeed)
+	assert.Nil(t, err)
+	assert.Equal(t, gofeed.DateSourceFeed, feed.Items[2].DateSource)
+	assert.Equal(t, 3, feed.Items[2].DateParsed.Day())
+
+	translator.FirstSeen = nil
+	rssFeed.PubDateParsed = nil
+	feed, err = translator.Translate(rssFeed)
+	assert.Nil(t, err)
+	assert.Nil(t, feed.Items[2].DateParsed)
+	assert.Equal(t, "", feed.Items[2].DateSource)
+}
+
 func TestDefaultAtomTranslator_Translate(t *testing.T) {
 	files, _ := filepath.Glob("testdata/translator/atom/*.xml")
 	for _, f := range files {
@@ -99,6 +157,94 @@ func TestDefaultAtomTranslator_Translate_WrongType(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestDefaultActivityStreamsTranslator_Translate(t *testing.T) {
+	files, _ := filepath.Glob("testdata/translator/activitystreams/*.json")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		if strings.HasSuffix(name, "expected") {
+			continue
+		}
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("testdata/translator/activitystreams/%s.json", name)
+		f, _ := os.Open(ff)
+		defer f.Close()
+
+		// Parse actual feed
+		translator := &gofeed.DefaultActivityStreamsTranslator{}
+		fp := activitystreams.Parser{}
+		collection, _ := fp.Parse(f)
+		actual, _ := translator.Translate(collection)
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("testdata/translator/activitystreams/%s_expected.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &gofeed.Feed{}
+		jsonEncoding.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.json did not match expected output %s_expected.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestDefaultActivityStreamsTranslator_Translate_WrongType(t *testing.T) {
+	translator := &gofeed.DefaultActivityStreamsTranslator{}
+	af, err := translator.Translate("wrong type")
+	assert.Nil(t, af)
+	assert.NotNil(t, err)
+}
+
+func TestDefaultHFeedTranslator_Translate(t *testing.T) {
+	files, _ := filepath.Glob("testdata/translator/hfeed/*.html")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("testdata/translator/hfeed/%s.html", name)
+		f, _ := os.Open(ff)
+		defer f.Close()
+
+		// Parse actual feed
+		translator := &gofeed.DefaultHFeedTranslator{}
+		fp := &hfeed.Parser{}
+		hfeedFeed, _ := fp.Parse(f)
+		actual, _ := translator.Translate(hfeedFeed)
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("testdata/translator/hfeed/%s.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &gofeed.Feed{}
+		jsonEncoding.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.html did not match expected output %s.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestDefaultHFeedTranslator_Translate_WrongType(t *testing.T) {
+	translator := &gofeed.DefaultHFeedTranslator{}
+	hf, err := translator.Translate("wrong type")
+	assert.Nil(t, hf)
+	assert.NotNil(t, err)
+}
+
 func TestDefaultJSONTranslator_Translate(t *testing.T) {
 	files, _ := filepath.Glob("testdata/translator/json/*.json")
 	for _, f := range files {